@@ -0,0 +1,86 @@
+package dictpool
+
+// DefaultAdaptiveThresholds are the thresholds used by an adaptive dict
+// when no other thresholds have been configured.
+var DefaultAdaptiveThresholds = AdaptiveThresholds{
+	Binary: 16,
+	Hash:   64,
+}
+
+// AdaptiveThresholds configures when an adaptive dict changes its lookup strategy.
+//
+// A zero value means the default threshold and a negative one disables the strategy.
+type AdaptiveThresholds struct {
+	// Binary is the number of keys from which the dict
+	// is sorted and searched with binary search.
+	Binary int
+
+	// Hash is the number of keys from which the dict
+	// is searched with a hash index, keeping the order of D.
+	Hash int
+}
+
+func (t AdaptiveThresholds) binary() int {
+	if t.Binary == 0 {
+		return DefaultAdaptiveThresholds.Binary
+	}
+
+	return t.Binary
+}
+
+func (t AdaptiveThresholds) hash() int {
+	if t.Hash == 0 {
+		return DefaultAdaptiveThresholds.Hash
+	}
+
+	return t.Hash
+}
+
+func (t AdaptiveThresholds) useBinary(n int) bool {
+	threshold := t.binary()
+
+	return threshold > 0 && n >= threshold
+}
+
+func (t AdaptiveThresholds) useHash(n int) bool {
+	threshold := t.hash()
+
+	return threshold > 0 && n >= threshold
+}
+
+// adapt updates the lookup strategy of an adaptive dict according to its length.
+func (d *Dict) adapt() {
+	if !d.adaptive {
+		return
+	}
+
+	n := d.len()
+	hashed := d.thresholds.useHash(n)
+
+	d.BinarySearch = !hashed && d.thresholds.useBinary(n)
+	d.setHashIndex(hashed)
+
+	// Sort now so the lookups never modify the dict.
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+}
+
+// SetAdaptive enables or disables the adaptive lookup strategy.
+//
+// An adaptive dict uses the linear search while it's small and switches
+// to the binary search and then to the hash index when it grows,
+// so BinarySearch and the hash index are managed by the dict.
+// It downgrades again to the linear search on Reset.
+//
+// The ordered queries, e.g. Min or RangePrefix, sort it on demand in every stage.
+func (d *Dict) SetAdaptive(enabled bool) {
+	d.adaptive = enabled
+	d.adapt()
+}
+
+// SetAdaptiveThresholds sets the thresholds used by the adaptive lookup strategy.
+func (d *Dict) SetAdaptiveThresholds(t AdaptiveThresholds) {
+	d.thresholds = t
+	d.adapt()
+}
@@ -0,0 +1,93 @@
+package dictpool
+
+import "testing"
+
+func TestDict_SetAdaptive(t *testing.T) {
+	d := AcquireDict()
+	d.SetAdaptive(true)
+
+	keys := genKeys(t, DefaultAdaptiveThresholds.Binary*2)
+
+	for i, k := range keys {
+		d.Set(k, i)
+
+		if want := d.Len() >= DefaultAdaptiveThresholds.Binary; d.BinarySearch != want {
+			t.Fatalf("Dict.BinarySearch == %v with %d keys, want %v", d.BinarySearch, d.Len(), want)
+		}
+	}
+
+	for i, k := range keys {
+		if val := d.Get(k); val != i {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+
+	d.Reset()
+
+	if d.BinarySearch {
+		t.Error("Dict.Reset() has not been downgraded the lookup strategy")
+	}
+
+	d.SetAdaptive(false)
+	ReleaseDict(d)
+}
+
+func TestDict_SetAdaptiveThresholds(t *testing.T) {
+	d := AcquireDict()
+	d.SetAdaptive(true)
+	d.SetAdaptiveThresholds(AdaptiveThresholds{Binary: 2})
+
+	d.Set("b", 1)
+
+	if d.BinarySearch {
+		t.Error("Dict.BinarySearch enabled below the threshold")
+	}
+
+	d.Set("a", 2)
+
+	if !d.BinarySearch {
+		t.Error("Dict.BinarySearch not enabled at the threshold")
+	}
+
+	if val := d.Get("b"); val != 1 {
+		t.Errorf("Dict.Get() = '%v', want '%v'", val, 1)
+	}
+
+	d.SetAdaptiveThresholds(AdaptiveThresholds{Binary: -1})
+
+	if d.BinarySearch {
+		t.Error("Dict.BinarySearch enabled with the strategy disabled")
+	}
+
+	d.SetAdaptiveThresholds(AdaptiveThresholds{}) // nolint:exhaustruct
+	d.SetAdaptive(false)
+	ReleaseDict(d)
+}
+
+func TestDict_ParseAdaptive(t *testing.T) {
+	m := make(DictMap)
+	for i, k := range genKeys(t, DefaultAdaptiveThresholds.Binary) {
+		m[k] = i
+	}
+
+	d := AcquireDict()
+	d.SetAdaptive(true)
+	d.Parse(m)
+
+	if !d.BinarySearch {
+		t.Error("Dict.Parse() has not been upgraded the lookup strategy")
+	}
+
+	for k, v := range m {
+		if val := d.Get(k); val != v {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, v)
+		}
+	}
+
+	d.SetAdaptive(false)
+	ReleaseDict(d)
+}
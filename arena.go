@@ -0,0 +1,102 @@
+package dictpool
+
+import (
+	"iter"
+	"unsafe"
+)
+
+const minKeyArenaSize = 256
+
+// keyArena stores the copies of the keys owned by a dict,
+// so they do not alias the buffers of the caller.
+//
+// A full buffer is replaced by a new one sized after the live keys, which are
+// moved to it, so the bytes of the deleted keys are not kept. The old buffer
+// is not modified, so the keys are valid until the arena is reset and its
+// buffer is reused.
+type keyArena struct {
+	buf []byte
+}
+
+// keyRefs yields a pointer to the key of each element,
+// so the key arena could move them.
+func keyRefs[T any](kvs []T, key func(*T) *string) iter.Seq[*string] {
+	return func(yield func(*string) bool) {
+		for i := range kvs {
+			if !yield(key(&kvs[i])) {
+				return
+			}
+		}
+	}
+}
+
+// owns reports whether the key is stored in the buffer.
+func owns(buf []byte, key string) bool {
+	if len(key) == 0 || len(buf) == 0 {
+		return false
+	}
+
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(buf)))
+	p := uintptr(unsafe.Pointer(unsafe.StringData(key)))
+
+	return p >= start && p < start+uintptr(len(buf))
+}
+
+// intern returns a copy of the key stored in the arena.
+//
+// keys yields the keys of the dict, which are moved if the buffer is full.
+func (a *keyArena) intern(key string, keys iter.Seq[*string]) string {
+	n := len(key)
+	if n == 0 {
+		return ""
+	}
+
+	if cap(a.buf)-len(a.buf) < n {
+		a.rebuild(a.buf, n, keys)
+	}
+
+	return a.append(key)
+}
+
+// rebuild replaces the buffer by a new one with room for n more bytes,
+// moving to it the keys stored in owner.
+//
+// The new buffer is at least as big as the number of keys,
+// so the cost of scanning them is amortized by the following interns.
+func (a *keyArena) rebuild(owner []byte, n int, keys iter.Seq[*string]) {
+	size, count := n, 0
+
+	for key := range keys {
+		if owns(owner, *key) {
+			size += len(*key)
+		}
+
+		count++
+	}
+
+	a.buf = make([]byte, 0, max(2*size, count, minKeyArenaSize))
+
+	for key := range keys {
+		if owns(owner, *key) {
+			*key = a.append(*key)
+		}
+	}
+}
+
+func (a *keyArena) append(key string) string {
+	start := len(a.buf)
+	a.buf = append(a.buf, key...)
+
+	return unsafe.String(&a.buf[start], len(key))
+}
+
+// dropOversized drops a buffer exceeding MaxRetainedKeysSize.
+func (a *keyArena) dropOversized() {
+	if exceedsCapacity(cap(a.buf), MaxRetainedKeysSize) {
+		a.buf = nil
+	}
+}
+
+func (a *keyArena) reset() {
+	a.buf = a.buf[:0]
+}
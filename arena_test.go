@@ -0,0 +1,83 @@
+package dictpool
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+// sliceKeyRefs yields a pointer to each key of the slice.
+func sliceKeyRefs(keys []string) func(yield func(*string) bool) {
+	return func(yield func(*string) bool) {
+		for i := range keys {
+			if !yield(&keys[i]) {
+				return
+			}
+		}
+	}
+}
+
+func TestKeyArena_Intern(t *testing.T) {
+	var (
+		a    keyArena
+		keys []string
+	)
+
+	buf := []byte("key")
+	keys = append(keys, a.intern(strconv.B2S(buf), sliceKeyRefs(keys)))
+
+	copy(buf, "bar")
+
+	if keys[0] != "key" {
+		t.Errorf("keyArena.intern() = %q, want %q", keys[0], "key")
+	}
+
+	if a.intern("", sliceKeyRefs(keys)) != "" {
+		t.Error("keyArena.intern() empty key")
+	}
+
+	// Growing the arena moves the live keys without modifying the old ones.
+	key := keys[0]
+	long := strings.Repeat("x", 2*minKeyArenaSize)
+
+	if got := a.intern(long, sliceKeyRefs(keys)); got != long {
+		t.Errorf("keyArena.intern() = %q, want %q", got, long)
+	}
+
+	if key != "key" || keys[0] != "key" {
+		t.Errorf("keyArena.intern() key modified on growth: %q %q", key, keys[0])
+	}
+
+	if !owns(a.buf, keys[0]) || owns(a.buf, key) {
+		t.Error("keyArena.intern() did not move the live keys on growth")
+	}
+
+	capacity := cap(a.buf)
+	a.reset()
+
+	if len(a.buf) != 0 || cap(a.buf) != capacity {
+		t.Errorf("keyArena.reset() len = %d cap = %d, want 0 and %d", len(a.buf), cap(a.buf), capacity)
+	}
+}
+
+func TestKeyArena_InternDeleted(t *testing.T) {
+	var a keyArena
+
+	keys := []string{"foo", "bar"}
+
+	// The bytes of the deleted keys are dropped when the arena is full.
+	for i := 0; i < 100000; i++ {
+		keys = append(keys, a.intern("0123456789abcdef", sliceKeyRefs(keys)))
+		keys = keys[:len(keys)-1]
+	}
+
+	if cap(a.buf) > minKeyArenaSize {
+		t.Errorf("keyArena capacity == %d, want <= %d", cap(a.buf), minKeyArenaSize)
+	}
+
+	if !slices.Equal(keys, []string{"foo", "bar"}) || owns(a.buf, keys[0]) {
+		t.Errorf("keyArena.intern() moved the keys not owned by the arena: %v", keys)
+	}
+}
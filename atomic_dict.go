@@ -0,0 +1,284 @@
+package dictpool
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+const (
+	// readerStripes is the number of reader counters per epoch,
+	// so the readers do not write the same cache line.
+	readerStripes = 16
+
+	// maxRetiredSnapshots is the number of replaced snapshots waiting for
+	// their readers, the older ones are left to the GC instead of reused.
+	maxRetiredSnapshots = 8
+)
+
+var (
+	defaultAtomicPool = sync.Pool{
+		New: func() interface{} {
+			return NewAtomicDict()
+		},
+	}
+
+	snapshotPool = sync.Pool{
+		New: func() interface{} {
+			return new(Dict)
+		},
+	}
+)
+
+// readerCount counts the readers of an epoch, padded to its own cache line.
+type readerCount struct {
+	n atomic.Int64
+	_ [56]byte
+}
+
+func (c *readerCount) release() {
+	c.n.Add(-1)
+}
+
+// retiredSnapshot is a replaced snapshot and the epoch when it was replaced.
+type retiredSnapshot struct {
+	d     *Dict
+	epoch uint64
+}
+
+// AtomicDict concurrency-safe dictionary for read-mostly workloads.
+//
+// The readers use the current immutable snapshot without locking nor retrying,
+// while the writers copy it to a pooled snapshot, apply their changes and
+// publish the copy.
+//
+// The readers are counted by epoch. The writers advance the epoch when
+// the readers of the previous one are done, and return a replaced snapshot
+// to the pool two epochs later, when no reader could be using it.
+//
+// The snapshots use the adaptive lookup strategy unless changed with Update.
+type AtomicDict struct {
+	mu  sync.Mutex
+	cur atomic.Pointer[Dict]
+
+	epoch   atomic.Uint64
+	readers [2][readerStripes]readerCount
+	retired []retiredSnapshot
+}
+
+// NewAtomicDict returns a new empty atomic dict.
+func NewAtomicDict() *AtomicDict {
+	return new(AtomicDict)
+}
+
+// AcquireAtomicDict acquire new atomic dict.
+func AcquireAtomicDict() *AtomicDict {
+	return defaultAtomicPool.Get().(*AtomicDict) // nolint:forcetypeassert
+}
+
+// ReleaseAtomicDict release atomic dict.
+func ReleaseAtomicDict(ad *AtomicDict) {
+	ad.Reset()
+	defaultAtomicPool.Put(ad)
+}
+
+// acquire returns the current snapshot, or nil if the atomic dict is empty,
+// and the counter that must be released when it's not used anymore.
+func (ad *AtomicDict) acquire() (*Dict, *readerCount) {
+	c := &ad.readers[ad.epoch.Load()&1][rand.Uint32()%readerStripes]
+	c.n.Add(1)
+
+	return ad.cur.Load(), c
+}
+
+func (ad *AtomicDict) readersOf(epoch uint64) int64 {
+	var n int64
+
+	for i := range ad.readers[epoch&1] {
+		n += ad.readers[epoch&1][i].n.Load()
+	}
+
+	return n
+}
+
+// publish replaces the current snapshot, retiring the previous one.
+func (ad *AtomicDict) publish(d *Dict) {
+	if old := ad.cur.Swap(d); old != nil {
+		ad.retired = append(ad.retired, retiredSnapshot{d: old, epoch: ad.epoch.Load()})
+	}
+
+	ad.reclaim()
+}
+
+// reclaim advances the epoch if the readers of the previous one are done,
+// and returns to the pool the snapshots retired two epochs ago or before.
+//
+// A reader of a snapshot was counted before it was replaced, in any epoch,
+// so it's done once both epochs have been checked after the replacement.
+func (ad *AtomicDict) reclaim() {
+	epoch := ad.epoch.Load()
+
+	if ad.readersOf(epoch+1) == 0 {
+		epoch++
+		ad.epoch.Store(epoch)
+	}
+
+	n := 0
+
+	for _, r := range ad.retired {
+		if epoch >= r.epoch+2 {
+			releaseSnapshot(r.d)
+		} else {
+			ad.retired[n] = r
+			n++
+		}
+	}
+
+	// The snapshots still used by slow readers are left to the GC.
+	if drop := n - maxRetiredSnapshots; drop > 0 {
+		n = copy(ad.retired, ad.retired[drop:n])
+	}
+
+	clear(ad.retired[n:])
+	ad.retired = ad.retired[:n]
+}
+
+func releaseSnapshot(d *Dict) {
+	d.reset()
+
+	// The keys of the arena could be shared with the next snapshots.
+	d.keys = keyArena{}
+	d.dropOversized(MaxRetainedCapacity)
+	snapshotPool.Put(d)
+}
+
+// Update calls fn with a copy of the current data and publishes it when fn returns.
+//
+// It's useful to apply several changes at once. The dict must not be used after fn returns.
+func (ad *AtomicDict) Update(fn func(d *Dict)) {
+	ad.mu.Lock()
+	defer ad.mu.Unlock()
+
+	d := snapshotPool.Get().(*Dict) // nolint:forcetypeassert
+
+	if old := ad.cur.Load(); old != nil {
+		old.copyTo(d)
+	} else {
+		// Drop the options of the previous user of the pooled snapshot.
+		var empty Dict
+
+		empty.copyTo(d)
+		d.SetAdaptive(true)
+	}
+
+	fn(d)
+
+	// Sort now so the lookups never modify the snapshot.
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	ad.publish(d)
+}
+
+// Len returns the number of keys of the atomic dict.
+func (ad *AtomicDict) Len() int {
+	d, c := ad.acquire()
+	defer c.release()
+
+	if d == nil {
+		return 0
+	}
+
+	return d.len()
+}
+
+// Get get data from key.
+func (ad *AtomicDict) Get(key string) interface{} {
+	d, c := ad.acquire()
+	defer c.release()
+
+	if d == nil {
+		return nil
+	}
+
+	return d.get(key)
+}
+
+// GetBytes get data from key.
+func (ad *AtomicDict) GetBytes(key []byte) interface{} {
+	return ad.Get(strconv.B2S(key))
+}
+
+// Has check if key exists.
+func (ad *AtomicDict) Has(key string) bool {
+	d, c := ad.acquire()
+	defer c.release()
+
+	if d == nil {
+		return false
+	}
+
+	return d.has(key)
+}
+
+// HasBytes check if key exists.
+func (ad *AtomicDict) HasBytes(key []byte) bool {
+	return ad.Has(strconv.B2S(key))
+}
+
+// Range calls fn sequentially for each key and value of the current snapshot.
+// If fn returns false, range stops the iteration.
+//
+// The changes made from fn are not seen by the iteration.
+func (ad *AtomicDict) Range(fn func(key string, value interface{}) bool) {
+	d, c := ad.acquire()
+	defer c.release()
+
+	if d == nil {
+		return
+	}
+
+	for i := range d.D {
+		if kv := &d.D[i]; !fn(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
+
+// Set set new key.
+func (ad *AtomicDict) Set(key string, value interface{}) {
+	ad.Update(func(d *Dict) {
+		d.set(key, value)
+	})
+}
+
+// SetBytes set new key.
+//
+// The key is copied if it's added, so the buffer could be reused.
+func (ad *AtomicDict) SetBytes(key []byte, value interface{}) {
+	ad.Update(func(d *Dict) {
+		d.setBytes(strconv.B2S(key), value)
+	})
+}
+
+// Del delete key.
+func (ad *AtomicDict) Del(key string) {
+	ad.Update(func(d *Dict) {
+		d.del(key)
+	})
+}
+
+// DelBytes delete key.
+func (ad *AtomicDict) DelBytes(key []byte) {
+	ad.Del(strconv.B2S(key))
+}
+
+// Reset reset atomic dict.
+func (ad *AtomicDict) Reset() {
+	ad.mu.Lock()
+	ad.publish(nil)
+	ad.mu.Unlock()
+}
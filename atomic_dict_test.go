@@ -0,0 +1,298 @@
+package dictpool
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestAtomicDict(t *testing.T) {
+	ad := AcquireAtomicDict()
+
+	if ad.Get("missing") != nil || ad.Has("missing") || ad.Len() != 0 {
+		t.Error("AtomicDict empty dict returns data")
+	}
+
+	keys := genKeys(t, 100)
+	for i, k := range keys {
+		ad.Set(k, i)
+	}
+
+	ad.SetBytes([]byte("bytes"), true)
+
+	if ad.Len() != len(keys)+1 {
+		t.Errorf("AtomicDict.Len() == %d, want %d", ad.Len(), len(keys)+1)
+	}
+
+	for i, k := range keys {
+		if val := ad.Get(k); val != i {
+			t.Errorf("AtomicDict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	if ad.GetBytes([]byte("bytes")) != true || !ad.HasBytes([]byte("bytes")) {
+		t.Error("AtomicDict.SetBytes() not set the new key and value")
+	}
+
+	ad.Del(keys[0])
+	ad.DelBytes([]byte("bytes"))
+
+	if ad.Has(keys[0]) || ad.Has("bytes") {
+		t.Error("AtomicDict.Del() not delete the key")
+	}
+
+	ReleaseAtomicDict(ad)
+
+	if ad.Len() != 0 {
+		t.Error("the atomic dict has not been reseted")
+	}
+}
+
+func TestAtomicDict_Update(t *testing.T) {
+	ad := AcquireAtomicDict()
+
+	ad.Update(func(d *Dict) {
+		d.SetAdaptive(false)
+		d.SetBinarySearch(true)
+		d.Set("b", 1)
+		d.Set("a", 2)
+	})
+
+	var keys []string
+
+	ad.Range(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("AtomicDict.Range() keys == %v, want %v", keys, []string{"a", "b"})
+	}
+
+	ReleaseAtomicDict(ad)
+
+	// A pooled atomic dict must not keep the options of the previous user.
+	ad = AcquireAtomicDict()
+	ad.Set("b", 1)
+	ad.Set("a", 2)
+
+	if ad.cur.Load().BinarySearch {
+		t.Error("AtomicDict snapshot kept the options of a previous dict")
+	}
+
+	ReleaseAtomicDict(ad)
+}
+
+func TestAtomicDict_Snapshot(t *testing.T) {
+	ad := NewAtomicDict()
+	ad.Set("key", 1)
+
+	d, c := ad.acquire()
+
+	for i := 2; i < 10; i++ {
+		ad.Set("key", i)
+	}
+
+	// The snapshot is not reused while a reader uses it.
+	if val := d.get("key"); val != 1 {
+		t.Errorf("snapshot value = '%v', want '%v'", val, 1)
+	}
+
+	if n := len(ad.retired); n == 0 || ad.retired[0].d != d {
+		t.Errorf("AtomicDict retired %d snapshots, want the one in use first", n)
+	}
+
+	c.release()
+
+	for i := 10; i < 20; i++ {
+		ad.Set("key", i)
+	}
+
+	// The replaced snapshots are returned to the pool once the readers are done.
+	if n := len(ad.retired); n > 2 {
+		t.Errorf("AtomicDict retired %d snapshots, want <= %d", n, 2)
+	}
+
+	if val := ad.Get("key"); val != 19 {
+		t.Errorf("AtomicDict.Get() = '%v', want '%v'", val, 19)
+	}
+}
+
+func TestAtomicDict_SlowReader(t *testing.T) {
+	ad := NewAtomicDict()
+	ad.Set("key", 0)
+
+	d, c := ad.acquire()
+
+	for i := 1; i <= 100; i++ {
+		ad.Set("key", i)
+	}
+
+	// The snapshots waiting for a slow reader are bounded.
+	if n := len(ad.retired); n > maxRetiredSnapshots {
+		t.Errorf("AtomicDict retired %d snapshots, want <= %d", n, maxRetiredSnapshots)
+	}
+
+	if val := d.get("key"); val != 0 {
+		t.Errorf("snapshot value = '%v', want '%v'", val, 0)
+	}
+
+	c.release()
+}
+
+func TestAtomicDict_Concurrency(t *testing.T) {
+	const readers, writes = 8, 500
+
+	ad := AcquireAtomicDict()
+
+	var wg sync.WaitGroup
+
+	done := make(chan struct{})
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				// Every snapshot must be consistent: "n" is the number of other keys.
+				n, _ := ad.Get("n").(int)
+
+				count := 0
+
+				ad.Range(func(key string, value interface{}) bool {
+					if key != "n" {
+						count++
+					} else {
+						n = value.(int) // nolint:forcetypeassert
+					}
+
+					return true
+				})
+
+				if count != n {
+					t.Errorf("AtomicDict inconsistent snapshot: %d keys, want %d", count, n)
+
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < writes; i++ {
+		ad.Update(func(d *Dict) {
+			d.Set(strconv.Itoa(i), i)
+			d.Set("n", i+1)
+		})
+	}
+
+	close(done)
+	wg.Wait()
+
+	if ad.Len() != writes+1 {
+		t.Errorf("AtomicDict.Len() == %d, want %d", ad.Len(), writes+1)
+	}
+
+	ReleaseAtomicDict(ad)
+}
+
+func BenchmarkAtomicDict(b *testing.B) {
+	keys := genKeys(b, 100)
+	ad := AcquireAtomicDict()
+
+	for i, k := range keys {
+		ad.Set(k, i)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+
+		for pb.Next() {
+			ad.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkAtomicDict_RWMutex(b *testing.B) {
+	keys := genKeys(b, 100)
+
+	var mu sync.RWMutex
+
+	d := AcquireDict()
+	d.SetAdaptive(true)
+
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+
+		for pb.Next() {
+			mu.RLock()
+			d.Get(keys[i%len(keys)])
+			mu.RUnlock()
+			i++
+		}
+	})
+}
+
+func TestAtomicDict_SetBytesOwnedKey(t *testing.T) {
+	ad := NewAtomicDict()
+
+	buf := []byte("foo")
+	ad.SetBytes(buf, 1)
+
+	copy(buf, "bar")
+
+	for i := 0; i < 10; i++ {
+		ad.SetBytes([]byte("key"+strconv.Itoa(i)), i)
+	}
+
+	if v := ad.Get("foo"); v != 1 {
+		t.Errorf("AtomicDict.Get() = '%v', want '%v'", v, 1)
+	}
+
+	for i := 0; i < 10; i++ {
+		if v := ad.Get("key" + strconv.Itoa(i)); v != i {
+			t.Errorf("AtomicDict.Get() = '%v', want '%v'", v, i)
+		}
+	}
+}
+
+func TestAtomicDict_RangeKeptKeys(t *testing.T) {
+	ad := NewAtomicDict()
+	ad.Set("alpha", 1)
+	ad.Set("beta", 2)
+
+	var keys []string
+
+	ad.Range(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	ad.Del("alpha")
+
+	for i := 0; i < 50; i++ {
+		ad.SetBytes([]byte("beta"+strconv.Itoa(i)), i)
+	}
+
+	if len(keys) != 2 || keys[0] != "alpha" || keys[1] != "beta" {
+		t.Errorf("AtomicDict.Range() keys == %v, want %v", keys, []string{"alpha", "beta"})
+	}
+}
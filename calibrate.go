@@ -0,0 +1,95 @@
+package dictpool
+
+import "sync/atomic"
+
+const (
+	calibrateCallsThreshold = 42000
+	calibratePercentile     = 0.95
+	calibrateMaxFactor      = 4
+)
+
+// poolCalibration learns the typical capacity of the dicts released to a pool,
+// like bytebufferpool does with its buffers.
+//
+// The bucket 0 of calls counts the capacities up to 1 and the bucket i
+// the ones in (2^(i-1), 2^i], so 2^i is the capacity to preallocate.
+type poolCalibration struct {
+	calls       sizeCounters
+	count       atomic.Uint64
+	calibrating atomic.Bool
+	defaultCap  atomic.Int64
+	maxCap      atomic.Int64
+}
+
+func calibrationBucket(n int) int {
+	return sizeBucket(max(n-1, 0))
+}
+
+// record records the capacity of a released dict, calibrating the pool periodically.
+func (c *poolCalibration) record(n int) {
+	c.calls[calibrationBucket(n)].Add(1)
+
+	if c.count.Add(1) > calibrateCallsThreshold {
+		c.calibrate()
+	}
+}
+
+// calibrate computes the percentile of the recorded capacities
+// and restarts the recording.
+func (c *poolCalibration) calibrate() {
+	if !c.calibrating.CompareAndSwap(false, true) {
+		return
+	}
+
+	var (
+		calls SizeHistogram
+		sum   uint64
+	)
+
+	c.count.Store(0)
+
+	for i := range c.calls {
+		calls[i] = c.calls[i].Swap(0)
+		sum += calls[i]
+	}
+
+	if sum > 0 {
+		limit := uint64(float64(sum) * calibratePercentile)
+
+		var acc uint64
+
+		for i, n := range calls {
+			if acc += n; acc >= limit {
+				c.defaultCap.Store(1 << i)
+				c.maxCap.Store(calibrateMaxFactor << i)
+
+				break
+			}
+		}
+	}
+
+	c.calibrating.Store(false)
+}
+
+// initialCapacity returns the capacity of D for the new dicts.
+func (p *Pool) initialCapacity() int {
+	if p.Calibrate {
+		return max(p.InitialCapacity, int(p.calibration.defaultCap.Load()))
+	}
+
+	return p.InitialCapacity
+}
+
+// discard reports whether a released dict with the given capacity of D
+// is far above the calibrated capacity.
+func (p *Pool) discard(n int) bool {
+	if !p.Calibrate {
+		return false
+	}
+
+	p.calibration.record(n)
+
+	maxCap := p.calibration.maxCap.Load()
+
+	return maxCap > 0 && int64(n) > maxCap
+}
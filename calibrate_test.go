@@ -0,0 +1,113 @@
+package dictpool
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCalibrationBucket(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 0, want: 0},
+		{n: 1, want: 0},
+		{n: 2, want: 1},
+		{n: 3, want: 2},
+		{n: 4, want: 2},
+		{n: 16, want: 4},
+		{n: 17, want: 5},
+	}
+
+	for _, test := range tests {
+		if got := calibrationBucket(test.n); got != test.want {
+			t.Errorf("calibrationBucket(%d) = %d, want %d", test.n, got, test.want)
+		}
+	}
+}
+
+func TestPoolCalibration_Calibrate(t *testing.T) {
+	c := new(poolCalibration)
+
+	for i := 0; i < 95; i++ {
+		c.calls[calibrationBucket(16)].Add(1)
+	}
+
+	for i := 0; i < 5; i++ {
+		c.calls[calibrationBucket(1024)].Add(1)
+	}
+
+	c.calibrate()
+
+	if n := c.defaultCap.Load(); n != 16 {
+		t.Errorf("poolCalibration.calibrate() defaultCap = %d, want %d", n, 16)
+	}
+
+	if n := c.maxCap.Load(); n != 16*calibrateMaxFactor {
+		t.Errorf("poolCalibration.calibrate() maxCap = %d, want %d", n, 16*calibrateMaxFactor)
+	}
+
+	for i := range c.calls {
+		if n := c.calls[i].Load(); n != 0 {
+			t.Errorf("poolCalibration.calibrate() bucket %d not restarted: %d", i, n)
+		}
+	}
+
+	// Calibrating without records keeps the previous values.
+	c.calibrate()
+
+	if n := c.defaultCap.Load(); n != 16 {
+		t.Errorf("poolCalibration.calibrate() defaultCap = %d, want %d", n, 16)
+	}
+}
+
+func TestPool_Calibrate(t *testing.T) {
+	p := &Pool{Calibrate: true}
+	capacity := 0
+
+	for i := 0; i <= calibrateCallsThreshold; i++ {
+		d := p.Acquire()
+
+		for j := 0; j < 10; j++ {
+			d.Set(strconv.Itoa(j), j)
+		}
+
+		capacity = cap(d.D)
+		p.Release(d)
+	}
+
+	if n, want := p.initialCapacity(), 1<<calibrationBucket(capacity); n != want {
+		t.Errorf("Pool.initialCapacity() = %d, want %d", n, want)
+	}
+
+	big := New()
+
+	for i := 0; i < 1000; i++ {
+		big.Set(strconv.Itoa(i), i)
+	}
+
+	p.Release(big)
+
+	if big.Len() == 0 {
+		t.Error("Pool.Release() reset a discarded dict")
+	}
+
+	if n := p.Stats().Discarded; n != 1 {
+		t.Errorf("Pool.Stats() Discarded = %d, want %d", n, 1)
+	}
+}
+
+func TestPool_CalibrateInitialCapacity(t *testing.T) {
+	p := &Pool{Calibrate: true, InitialCapacity: 64}
+	p.calibration.defaultCap.Store(16)
+
+	if n := p.initialCapacity(); n != 64 {
+		t.Errorf("Pool.initialCapacity() = %d, want %d", n, 64)
+	}
+
+	p.InitialCapacity = 8
+
+	if d := p.Acquire(); cap(d.D) != 16 {
+		t.Errorf("Pool.Acquire() cap = %d, want %d", cap(d.D), 16)
+	}
+}
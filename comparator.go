@@ -0,0 +1,135 @@
+package dictpool
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+var foldBufPool = sync.Pool{
+	New: func() interface{} {
+		return new([]byte)
+	},
+}
+
+// Comparator returns a negative number if a sorts before b,
+// a positive number if a sorts after b and zero if they are equal.
+//
+// It must return zero only for equal keys. With a case-insensitive key mode,
+// it's called with the keys folded by the mode, so the keys equal in the mode
+// compare to zero. The folded keys are only valid during the call.
+type Comparator func(a, b string) int
+
+// NaturalCompare compares the keys in natural order,
+// so the digit sequences are compared by their numeric value, e.g. "item2" < "item10".
+//
+// The keys with the same natural order are compared byte-wise, e.g. "item02" < "item2".
+func NaturalCompare(a, b string) int {
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		ca, cb := a[i], b[j]
+
+		if !isDigit(ca) || !isDigit(cb) {
+			if ca != cb {
+				return int(ca) - int(cb)
+			}
+
+			i++
+			j++
+
+			continue
+		}
+
+		// Compare the numbers without their leading zeros,
+		// first by their number of digits and then digit by digit.
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+
+		si, sj := i, j
+
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+
+		if n, m := i-si, j-sj; n != m {
+			return n - m
+		}
+
+		if c := strings.Compare(a[si:i], b[sj:j]); c != 0 {
+			return c
+		}
+	}
+
+	if c := (len(a) - i) - (len(b) - j); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a, b)
+}
+
+// ReverseCompare compares the keys byte-wise in reverse order.
+func ReverseCompare(a, b string) int {
+	return strings.Compare(b, a)
+}
+
+// Reverse returns the comparator with the reverse order of cmp.
+func Reverse(cmp Comparator) Comparator {
+	return func(a, b string) int {
+		return cmp(b, a)
+	}
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// compare compares the keys with the comparator of the dict, if any,
+// or with its key mode.
+func (d *Dict) compare(a, b string) int {
+	switch {
+	case d.cmp == nil:
+		return d.keyMode.compare(a, b)
+	case d.keyMode == KeyExact:
+		return d.cmp(a, b)
+	}
+
+	return foldCompare(d.cmp, d.keyMode, a, b)
+}
+
+// foldCompare calls cmp with the keys folded by the key mode.
+func foldCompare(cmp Comparator, mode KeyMode, a, b string) int {
+	buf := foldBufPool.Get().(*[]byte) // nolint:forcetypeassert
+
+	*buf = mode.appendFold((*buf)[:0], a)
+	n := len(*buf)
+	*buf = mode.appendFold(*buf, b)
+
+	c := cmp(strconv.B2S((*buf)[:n]), strconv.B2S((*buf)[n:]))
+
+	if !exceedsCapacity(cap(*buf), MaxRetainedKeysSize) {
+		foldBufPool.Put(buf)
+	}
+
+	return c
+}
+
+// SetComparator sets the order of the keys used by the binary search,
+// or restores the default one if cmp is nil.
+func (d *Dict) SetComparator(cmp Comparator) {
+	d.cmp = cmp
+
+	if d.len() > 1 {
+		d.sorted = false
+	}
+}
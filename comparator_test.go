@@ -0,0 +1,214 @@
+package dictpool
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "item2", b: "item10", want: -1},
+		{a: "item10", b: "item2", want: 1},
+		{a: "item10", b: "item10", want: 0},
+		{a: "item02", b: "item2", want: -1},
+		{a: "item2", b: "item02", want: 1},
+		{a: "item2a", b: "item2b", want: -1},
+		{a: "item", b: "item1", want: -1},
+		{a: "a1b2", b: "a1b10", want: -1},
+		{a: "x99", b: "x100", want: -1},
+		{a: "", b: "0", want: -1},
+		{a: "b", b: "a100", want: 1},
+	}
+
+	sign := func(n int) int {
+		switch {
+		case n < 0:
+			return -1
+		case n > 0:
+			return 1
+		}
+
+		return 0
+	}
+
+	for _, test := range tests {
+		if got := sign(NaturalCompare(test.a, test.b)); got != test.want {
+			t.Errorf("NaturalCompare(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	if ReverseCompare("a", "b") <= 0 || ReverseCompare("b", "a") >= 0 || ReverseCompare("a", "a") != 0 {
+		t.Error("ReverseCompare() does not reverse the byte-wise order")
+	}
+
+	cmp := Reverse(NaturalCompare)
+
+	if cmp("item2", "item10") <= 0 {
+		t.Error("Reverse(NaturalCompare) does not reverse the natural order")
+	}
+}
+
+func TestDict_SetComparator(t *testing.T) {
+	comparators := map[string]Comparator{
+		"natural":         NaturalCompare,
+		"reverse":         ReverseCompare,
+		"reverse natural": Reverse(NaturalCompare),
+	}
+
+	rnd := rand.New(rand.NewSource(1)) // nolint:gosec
+
+	for name, cmp := range comparators {
+		for _, hashed := range []bool{false, true} {
+			d := New()
+			d.SetComparator(cmp)
+			d.SetBinarySearch(true)
+			d.SetHashIndex(hashed)
+
+			model := make(map[string]int)
+
+			for i := 0; i < 500; i++ {
+				key := "item" + strconv.Itoa(rnd.Intn(200))
+
+				if i%5 == 0 {
+					key = "item0" + strconv.Itoa(rnd.Intn(20))
+				}
+
+				switch rnd.Intn(4) {
+				case 0:
+					d.Del(key)
+					delete(model, key)
+				default:
+					d.Set(key, i)
+					model[key] = i
+				}
+			}
+
+			if err := d.Validate(); err != nil {
+				t.Errorf("%s: Dict.Validate() unexpected error: %v", name, err)
+			}
+
+			if d.Len() != len(model) {
+				t.Errorf("%s: Dict.Len() = %d, want %d", name, d.Len(), len(model))
+			}
+
+			for key, want := range model {
+				if v := d.Get(key); v != want {
+					t.Errorf("%s: Dict.Get(%q) = '%v', want '%v'", name, key, v, want)
+				}
+			}
+
+			keys := d.Keys(nil)
+
+			if !sort.SliceIsSorted(keys, func(i, j int) bool { return cmp(keys[i], keys[j]) < 0 }) {
+				t.Errorf("%s: Dict.Keys() not sorted by the comparator: %v", name, keys)
+			}
+		}
+	}
+}
+
+func TestDict_SetComparatorKeyMode(t *testing.T) {
+	comparators := map[string]Comparator{
+		"natural":         NaturalCompare,
+		"reverse":         ReverseCompare,
+		"reverse natural": Reverse(NaturalCompare),
+	}
+
+	prefixes := []string{"item", "Item", "ITEM", "b", "B", "\u03c3", "\u03a3", "\u03c2"}
+
+	rnd := rand.New(rand.NewSource(1)) // nolint:gosec
+
+	for _, mode := range []KeyMode{KeyFoldASCII, KeyFoldUnicode} {
+		for name, cmp := range comparators {
+			for _, hashed := range []bool{false, true} {
+				d := New()
+				d.SetKeyMode(mode)
+				d.SetComparator(cmp)
+				d.SetBinarySearch(true)
+				d.SetHashIndex(hashed)
+
+				model := make(map[string]int)
+
+				for i := 0; i < 500; i++ {
+					key := prefixes[rnd.Intn(len(prefixes))] + strconv.Itoa(rnd.Intn(50))
+
+					switch rnd.Intn(4) {
+					case 0:
+						d.Del(key)
+						delete(model, mode.fold(key))
+					default:
+						d.Set(key, i)
+						model[mode.fold(key)] = i
+					}
+				}
+
+				if err := d.Validate(); err != nil {
+					t.Errorf("%d %s: Dict.Validate() unexpected error: %v", mode, name, err)
+				}
+
+				if d.Len() != len(model) {
+					t.Errorf("%d %s: Dict.Len() = %d, want %d", mode, name, d.Len(), len(model))
+				}
+
+				for _, kv := range d.D {
+					if v, want := d.Get(kv.Key), model[mode.fold(kv.Key)]; v != want {
+						t.Errorf("%d %s: Dict.Get(%q) = '%v', want '%v'", mode, name, kv.Key, v, want)
+					}
+				}
+
+				keys := d.Keys(nil)
+
+				if !sort.SliceIsSorted(keys, func(i, j int) bool { return cmp(mode.fold(keys[i]), mode.fold(keys[j])) < 0 }) {
+					t.Errorf("%d %s: Dict.Keys() not sorted by the comparator: %v", mode, name, keys)
+				}
+			}
+		}
+	}
+
+	d := New()
+	d.SetKeyMode(KeyFoldASCII)
+	d.SetComparator(NaturalCompare)
+	d.SetBinarySearch(true)
+
+	for _, k := range []string{"B", "a", "b"} {
+		d.Set(k, k)
+	}
+
+	if !d.Has("b") || d.Len() != 2 {
+		t.Errorf("Dict.Has() = %v with %d keys, want true with 2 keys", d.Has("b"), d.Len())
+	}
+}
+
+func TestDict_SetComparatorSorted(t *testing.T) {
+	d := New()
+	d.SetBinarySearch(true)
+
+	for _, k := range []string{"item10", "item2", "item1"} {
+		d.Set(k, k)
+	}
+
+	d.SetComparator(NaturalCompare)
+
+	// The keys are sorted again by the next lookup.
+	if v := d.Get("item2"); v != "item2" {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, "item2")
+	}
+
+	if keys, want := d.Keys(nil), []string{"item1", "item2", "item10"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Dict.Keys() = %v, want %v", keys, want)
+	}
+
+	d.SetComparator(nil)
+	d.Get("item2")
+
+	if keys, want := d.Keys(nil), []string{"item1", "item10", "item2"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Dict.Keys() = %v, want %v", keys, want)
+	}
+}
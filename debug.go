@@ -0,0 +1,45 @@
+//go:build dictpool_debug
+
+package dictpool
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// debugState tracks the pool lifecycle of a dict in debug builds.
+type debugState struct {
+	released     bool
+	acquireStack []byte
+	releaseStack []byte
+}
+
+func (s *debugState) acquire() {
+	s.released = false
+	s.acquireStack = debug.Stack()
+	s.releaseStack = nil
+}
+
+// checkRelease panics if the dict has already been released.
+func (s *debugState) checkRelease() {
+	if s.released {
+		panic(s.error(ErrDoubleRelease, debug.Stack()))
+	}
+}
+
+func (s *debugState) release() {
+	s.released = true
+	s.releaseStack = debug.Stack()
+}
+
+// check panics if the dict is used after being released.
+func (s *debugState) check() {
+	if s.released {
+		panic(s.error(ErrUseAfterRelease, debug.Stack()))
+	}
+}
+
+func (s *debugState) error(err error, stack []byte) error {
+	return fmt.Errorf("dictpool: %w\n\nacquired at:\n%s\nreleased at:\n%s\nused at:\n%s",
+		err, s.acquireStack, s.releaseStack, stack)
+}
@@ -0,0 +1,14 @@
+//go:build !dictpool_debug
+
+package dictpool
+
+// debugState is zero-sized and does nothing without the dictpool_debug build tag.
+type debugState struct{}
+
+func (s *debugState) acquire() {}
+
+func (s *debugState) checkRelease() {}
+
+func (s *debugState) release() {}
+
+func (s *debugState) check() {}
@@ -0,0 +1,14 @@
+//go:build !dictpool_debug
+
+package dictpool
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestDebug_ZeroSize(t *testing.T) {
+	if size := unsafe.Sizeof(debugState{}); size != 0 {
+		t.Errorf("debugState size = %d, want %d", size, 0)
+	}
+}
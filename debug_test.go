@@ -0,0 +1,92 @@
+//go:build dictpool_debug
+
+package dictpool
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func expectPanic(t *testing.T, want error, fn func()) {
+	t.Helper()
+
+	defer func() {
+		t.Helper()
+
+		err, _ := recover().(error)
+		if !errors.Is(err, want) {
+			t.Fatalf("panic = '%v', want '%v'", err, want)
+		}
+
+		msg := err.Error()
+
+		for _, s := range []string{"acquired at:", "released at:", "used at:", "debug_test.go"} {
+			if !strings.Contains(msg, s) {
+				t.Errorf("panic message does not contain '%s': %s", s, msg)
+			}
+		}
+	}()
+
+	fn()
+}
+
+func TestDebug_DoubleRelease(t *testing.T) {
+	d := AcquireDict()
+	ReleaseDict(d)
+
+	expectPanic(t, ErrDoubleRelease, func() {
+		ReleaseDict(d)
+	})
+}
+
+func TestDebug_DoubleReleasePool(t *testing.T) {
+	p := &Pool{}
+
+	d := p.Acquire()
+	p.Release(d)
+
+	expectPanic(t, ErrDoubleRelease, func() {
+		p.Release(d)
+	})
+}
+
+func TestDebug_UseAfterRelease(t *testing.T) {
+	tests := map[string]func(d *Dict){
+		"Get":      func(d *Dict) { d.Get("key") },
+		"GetBytes": func(d *Dict) { d.GetBytes([]byte("key")) },
+		"Set":      func(d *Dict) { d.Set("key", "value") },
+		"SetBytes": func(d *Dict) { d.SetBytes([]byte("key"), "value") },
+		"Del":      func(d *Dict) { d.Del("key") },
+		"DelBytes": func(d *Dict) { d.DelBytes([]byte("key")) },
+		"Has":      func(d *Dict) { d.Has("key") },
+	}
+
+	for name, use := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := AcquireDict()
+			d.Set("key", "value")
+			ReleaseDict(d)
+
+			expectPanic(t, ErrUseAfterRelease, func() {
+				use(d)
+			})
+		})
+	}
+}
+
+func TestDebug_Reacquire(t *testing.T) {
+	p := &Pool{}
+
+	d := p.Acquire()
+	p.Release(d)
+
+	d = p.Acquire()
+	d.Set("key", "value")
+
+	if v := d.Get("key"); v != "value" {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, "value")
+	}
+
+	p.Release(d)
+}
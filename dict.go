@@ -1,6 +1,7 @@
 package dictpool
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/savsgio/gotils/strconv"
@@ -15,6 +16,7 @@ func (d *Dict) allocKV() *KV {
 
 	if cap(d.D) > n {
 		d.D = d.D[:n+1]
+		d.D[n] = KV{} // nolint:exhaustruct
 	} else {
 		d.D = append(d.D, KV{}) // nolint:exhaustruct
 	}
@@ -22,29 +24,127 @@ func (d *Dict) allocKV() *KV {
 	return &d.D[n]
 }
 
-func (d *Dict) append(key string, value interface{}) {
+func (d *Dict) append(key string, value interface{}) *KV {
 	kv := d.allocKV()
 	kv.Key = key
 	kv.Value = value
+
+	d.sorted = false
+
+	if d.hashed {
+		d.index.add(d, d.len()-1)
+	}
+
+	return kv
 }
 
-func (d *Dict) indexOf(key string) int {
-	n := d.len()
+// appendLoaded appends a key loaded in bulk, replacing the value of an equal key instead
+// if the keys are not exact, since their different spellings could collide.
+func (d *Dict) appendLoaded(key string, value interface{}) *KV {
+	if d.keyMode != KeyExact {
+		if idx := d.scan(key); idx > -1 {
+			kv := &d.D[idx]
+			kv.setValue(value)
 
-	if d.BinarySearch {
-		idx := sort.Search(n, func(i int) bool {
+			return kv
+		}
+	}
+
+	return d.append(key, value)
+}
+
+func (d *Dict) insert(idx int, key string, value interface{}) {
+	d.allocKV()
+	copy(d.D[idx+1:], d.D[idx:])
+
+	kv := &d.D[idx]
+	kv.Key = key
+	kv.Value = value
+	kv.owned = false
+
+	if d.hashed {
+		d.index.shift(idx, 1)
+		d.index.add(d, idx)
+	}
+}
+
+// search returns the position where the key is or should be inserted
+// to keep the slice sorted.
+func (d *Dict) search(key string) int {
+	if d.keyMode == KeyExact && d.cmp == nil {
+		return sort.Search(d.len(), func(i int) bool {
 			return key <= d.D[i].Key
 		})
+	}
 
-		if idx < n && d.D[idx].Key == key {
-			return idx
-		}
-	} else {
+	return sort.Search(d.len(), func(i int) bool {
+		return d.compare(key, d.D[i].Key) <= 0
+	})
+}
+
+// ensureSorted sorts the dict if it is not known to be sorted yet.
+func (d *Dict) ensureSorted() {
+	if d.sorted {
+		return
+	}
+
+	sort.Stable((*keySorter)(d))
+	d.sorted = true
+
+	if d.hashed {
+		d.index.build(d)
+	}
+}
+
+// loaded restores the lookup invariants after D has been filled in bulk.
+func (d *Dict) loaded() {
+	d.sorted = false
+
+	if d.hashed {
+		d.index.build(d)
+	}
+
+	d.adapt()
+}
+
+func (d *Dict) indexOf(key string) int {
+	d.debug.check()
+
+	if d.hashed {
+		return d.index.lookup(d, key)
+	}
+
+	if !d.BinarySearch {
+		return d.scan(key)
+	}
+
+	d.ensureSorted()
+
+	if idx := d.search(key); idx < d.len() && d.keyMode.equal(d.D[idx].Key, key) {
+		return idx
+	}
+
+	return -1
+}
+
+// scan returns the position of the key in the dict or -1, searching it sequentially.
+func (d *Dict) scan(key string) int {
+	n := d.len()
+
+	if d.keyMode == KeyExact {
 		for i := 0; i < n; i++ {
 			if d.D[i].Key == key {
 				return i
 			}
 		}
+
+		return -1
+	}
+
+	for i := 0; i < n; i++ {
+		if d.keyMode.equal(d.D[i].Key, key) {
+			return i
+		}
 	}
 
 	return -1
@@ -59,7 +159,11 @@ func (d *Dict) swap(i, j int) {
 }
 
 func (d *Dict) less(i, j int) bool {
-	return d.D[i].Key < d.D[j].Key
+	if d.keyMode == KeyExact && d.cmp == nil {
+		return d.D[i].Key < d.D[j].Key
+	}
+
+	return d.compare(d.D[i].Key, d.D[j].Key) < 0
 }
 
 func (d *Dict) get(key string) interface{} {
@@ -70,21 +174,148 @@ func (d *Dict) get(key string) interface{} {
 	return nil
 }
 
-func (d *Dict) set(key string, value interface{}) {
+// keyRef returns a pointer to the key.
+func (kv *KV) keyRef() *string {
+	return &kv.Key
+}
+
+// setValue replaces the value, which is no longer owned by the dict,
+// releasing the nested dicts owned by the previous one.
+//
+// Setting the same nested dict gives up its ownership without releasing it.
+func (kv *KV) setValue(value interface{}) {
+	if kv.owned {
+		if sub, ok := value.(*Dict); !ok || sub != kv.Value {
+			releaseValue(kv.Value)
+		}
+	}
+
+	kv.Value = value
+	kv.owned = false
+}
+
+func (d *Dict) lookup(key string) (interface{}, bool) {
 	if idx := d.indexOf(key); idx > -1 {
-		d.D[idx].Value = value
+		return d.D[idx].Value, true
+	}
+
+	return nil, false
+}
+
+// find returns the position of the key and true,
+// or the position where it should be added and false.
+func (d *Dict) find(key string) (int, bool) {
+	d.debug.check()
+
+	if d.BinarySearch && !d.hashed {
+		d.ensureSorted()
+
+		idx := d.search(key)
+
+		return idx, idx < d.len() && d.keyMode.equal(d.D[idx].Key, key)
+	}
+
+	if idx := d.indexOf(key); idx > -1 {
+		return idx, true
+	}
+
+	if d.BinarySearch {
+		d.ensureSorted()
+
+		return d.search(key), false
+	}
+
+	return d.len(), false
+}
+
+// add adds a new key at the position returned by find.
+func (d *Dict) add(idx int, key string, value interface{}) {
+	if d.BinarySearch {
+		d.insert(idx, key, value)
 	} else {
 		d.append(key, value)
+	}
 
-		if d.BinarySearch {
-			sort.Sort(d)
-		}
+	d.adapt()
+}
+
+func (d *Dict) set(key string, value interface{}) {
+	if idx, ok := d.find(key); ok {
+		d.D[idx].setValue(value)
+	} else {
+		d.add(idx, key, value)
+	}
+}
+
+// setBytes is like set with a key aliasing a byte slice,
+// which is copied to the key arena if it's added.
+func (d *Dict) setBytes(key string, value interface{}) {
+	if idx, ok := d.find(key); ok {
+		d.D[idx].setValue(value)
+	} else {
+		d.add(idx, d.keys.intern(key, keyRefs(d.D, (*KV).keyRef)), value)
+	}
+}
+
+// getOrSet returns the value of the key if present or sets the given one,
+// copying the key to the key arena if it aliases a byte slice.
+func (d *Dict) getOrSet(key string, value interface{}, aliased bool) (interface{}, bool) {
+	idx, ok := d.find(key)
+	if ok {
+		return d.D[idx].Value, true
+	}
+
+	if aliased {
+		key = d.keys.intern(key, keyRefs(d.D, (*KV).keyRef))
+	}
+
+	d.add(idx, key, value)
+
+	return value, false
+}
+
+// swapValue sets the value of the key and returns the previous one if any,
+// copying the key to the key arena if it aliases a byte slice.
+func (d *Dict) swapValue(key string, value interface{}, aliased bool) (interface{}, bool) {
+	idx, ok := d.find(key)
+	if ok {
+		// The previous value is returned, so it's not released.
+		previous := d.D[idx].Value
+		d.D[idx].owned = false
+		d.D[idx].setValue(value)
+
+		return previous, true
+	}
+
+	if aliased {
+		key = d.keys.intern(key, keyRefs(d.D, (*KV).keyRef))
+	}
+
+	d.add(idx, key, value)
+
+	return nil, false
+}
+
+func (d *Dict) delAt(idx int) {
+	if d.hashed {
+		d.index.remove(d.keyMode.hash(d.D[idx].Key), idx)
+		d.index.shift(idx+1, -1)
 	}
+
+	n := d.len()
+
+	copy(d.D[idx:], d.D[idx+1:])
+	d.D[n-1] = KV{} // nolint:exhaustruct
+	d.D = d.D[:n-1]
 }
 
 func (d *Dict) del(key string) {
 	if idx := d.indexOf(key); idx > -1 {
-		d.D = append(d.D[:idx], d.D[idx+1:]...)
+		if kv := &d.D[idx]; kv.owned {
+			releaseValue(kv.Value)
+		}
+
+		d.delAt(idx)
 	}
 }
 
@@ -92,8 +323,123 @@ func (d *Dict) has(key string) bool {
 	return d.indexOf(key) > -1
 }
 
+// copyTo copies the data and the options of the dict to dst, reusing its memory.
+//
+// The keys are shared, so the key arena of d must never be reused, e.g. by
+// a reset, while dst is used. dst gets a new key arena to not write over it.
+func (d *Dict) copyTo(dst *Dict) {
+	kvs, slots := dst.D, dst.index.slots
+
+	*dst = *d
+	dst.D = append(kvs[:0], d.D...)
+	dst.index.slots = append(slots[:0], d.index.slots...)
+	dst.keys = keyArena{}
+}
+
+// rangeKV calls fn for each key/value in order until it returns false.
+//
+// The current key could be deleted by fn without skipping the next one.
+func (d *Dict) rangeKV(fn func(key string, value interface{}) bool) {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	for i := 0; i < d.len(); {
+		key := d.D[i].Key
+
+		if !fn(key, d.D[i].Value) {
+			return
+		}
+
+		if i < d.len() && d.D[i].Key == key {
+			i++
+		}
+	}
+}
+
+// reload resets the dict to load it again,
+// releasing the nested dicts owned by it.
+func (d *Dict) reload() {
+	d.releaseOwned()
+	d.reset()
+}
+
 func (d *Dict) reset() {
+	clear(d.D)
+
 	d.D = d.D[:0]
+	d.sorted = true
+	d.index.clear()
+	d.keys.reset()
+
+	d.adapt()
+}
+
+func (d *Dict) validate() error {
+	n := d.len()
+
+	if d.BinarySearch && d.sorted {
+		for i := 1; i < n; i++ {
+			key := d.D[i].Key
+
+			switch cmp := d.compare(d.D[i-1].Key, key); {
+			case cmp == 0:
+				return fmt.Errorf("%w: %q at index %d", ErrDuplicateKey, key, i)
+			case cmp > 0:
+				return fmt.Errorf("%w: %q at index %d", ErrUnsortedKeys, key, i)
+			}
+		}
+
+		return d.validateIndex()
+	}
+
+	seen := make(map[string]struct{}, n)
+
+	for i := 0; i < n; i++ {
+		key := d.D[i].Key
+		folded := d.keyMode.fold(key)
+
+		if _, ok := seen[folded]; ok {
+			return fmt.Errorf("%w: %q at index %d", ErrDuplicateKey, key, i)
+		}
+
+		seen[folded] = struct{}{}
+	}
+
+	return d.validateIndex()
+}
+
+func (d *Dict) validateIndex() error {
+	if !d.hashed {
+		return nil
+	}
+
+	if d.index.count != d.len() {
+		return fmt.Errorf("%w: %d indexed keys, want %d", ErrStaleIndex, d.index.count, d.len())
+	}
+
+	for i := range d.D {
+		if idx := d.index.lookup(d, d.D[i].Key); idx != i {
+			return fmt.Errorf("%w: %q at index %d, want %d", ErrStaleIndex, d.D[i].Key, idx, i)
+		}
+	}
+
+	return nil
+}
+
+// keySorter sorts a dict by key without maintaining its hash index.
+type keySorter Dict
+
+func (s *keySorter) Len() int {
+	return (*Dict)(s).len()
+}
+
+func (s *keySorter) Swap(i, j int) {
+	(*Dict)(s).swap(i, j)
+}
+
+func (s *keySorter) Less(i, j int) bool {
+	return (*Dict)(s).less(i, j)
 }
 
 // Len is the number of elements in the Dict.
@@ -103,7 +449,12 @@ func (d *Dict) Len() int {
 
 // Swap swaps the elements with indexes i and j.
 func (d *Dict) Swap(i, j int) {
+	if d.hashed {
+		d.index.swap(d.keyMode.hash(d.D[i].Key), i, d.keyMode.hash(d.D[j].Key), j)
+	}
+
 	d.swap(i, j)
+	d.sorted = false
 }
 
 // Less reports whether the element with
@@ -112,6 +463,21 @@ func (d *Dict) Less(i, j int) bool {
 	return d.less(i, j)
 }
 
+// SetBinarySearch enables or disables the binary search.
+//
+// When enabled, the dict is sorted lazily on the next operation that needs it.
+func (d *Dict) SetBinarySearch(enabled bool) {
+	d.BinarySearch = enabled
+}
+
+// Validate reports whether the dict contains duplicate keys,
+// keys out of order in binary search mode or a stale hash index.
+//
+// It's intended for debugging since it could allocate memory.
+func (d *Dict) Validate() error {
+	return d.validate()
+}
+
 // Get get data from key.
 func (d *Dict) Get(key string) interface{} {
 	return d.get(key)
@@ -122,14 +488,91 @@ func (d *Dict) GetBytes(key []byte) interface{} {
 	return d.Get(strconv.B2S(key))
 }
 
+// Lookup returns the value of the key and whether it exists,
+// so a missing key and a nil value can be told apart.
+func (d *Dict) Lookup(key string) (interface{}, bool) {
+	return d.lookup(key)
+}
+
+// LookupBytes returns the value of the key and whether it exists.
+func (d *Dict) LookupBytes(key []byte) (interface{}, bool) {
+	return d.Lookup(strconv.B2S(key))
+}
+
+// GetOrSet returns the existing value of the key if present.
+// Otherwise, it sets and returns the given value.
+// The loaded result is true if the value was loaded, false if set.
+func (d *Dict) GetOrSet(key string, value interface{}) (actual interface{}, loaded bool) {
+	return d.getOrSet(key, value, false)
+}
+
+// GetOrSetBytes returns the existing value of the key if present.
+// Otherwise, it sets and returns the given value.
+func (d *Dict) GetOrSetBytes(key []byte, value interface{}) (actual interface{}, loaded bool) {
+	return d.getOrSet(strconv.B2S(key), value, true)
+}
+
+// SetIfAbsent sets the value only if the key does not exist,
+// reporting whether it has been set.
+func (d *Dict) SetIfAbsent(key string, value interface{}) bool {
+	_, loaded := d.getOrSet(key, value, false)
+
+	return !loaded
+}
+
+// SetIfAbsentBytes sets the value only if the key does not exist,
+// reporting whether it has been set.
+func (d *Dict) SetIfAbsentBytes(key []byte, value interface{}) bool {
+	_, loaded := d.getOrSet(strconv.B2S(key), value, true)
+
+	return !loaded
+}
+
+// SwapValue sets the value of the key and returns the previous value if any.
+// The loaded result reports whether the key was present.
+//
+// It's the equivalent of sync.Map.Swap, since Swap belongs to sort.Interface.
+// The nested dicts of the previous value are not released by the dict anymore.
+func (d *Dict) SwapValue(key string, value interface{}) (previous interface{}, loaded bool) {
+	return d.swapValue(key, value, false)
+}
+
+// SwapValueBytes sets the value of the key and returns the previous value if any.
+func (d *Dict) SwapValueBytes(key []byte, value interface{}) (previous interface{}, loaded bool) {
+	return d.swapValue(strconv.B2S(key), value, true)
+}
+
+// LoadAndDelete deletes the key, returning its previous value if any.
+// The loaded result reports whether the key was present.
+//
+// The nested dicts of the value are not released by the dict anymore.
+func (d *Dict) LoadAndDelete(key string) (value interface{}, loaded bool) {
+	idx := d.indexOf(key)
+	if idx < 0 {
+		return nil, false
+	}
+
+	value = d.D[idx].Value
+	d.delAt(idx)
+
+	return value, true
+}
+
+// LoadAndDeleteBytes deletes the key, returning its previous value if any.
+func (d *Dict) LoadAndDeleteBytes(key []byte) (value interface{}, loaded bool) {
+	return d.LoadAndDelete(strconv.B2S(key))
+}
+
 // Set set new key.
 func (d *Dict) Set(key string, value interface{}) {
 	d.set(key, value)
 }
 
 // SetBytes set new key.
+//
+// The key is copied if it's added, so the buffer could be reused.
 func (d *Dict) SetBytes(key []byte, value interface{}) {
-	d.Set(strconv.B2S(key), value)
+	d.setBytes(strconv.B2S(key), value)
 }
 
 // Del delete key.
@@ -153,10 +596,63 @@ func (d *Dict) HasBytes(key []byte) bool {
 }
 
 // Reset reset dict.
+//
+// The references of the keys and values are cleared,
+// so they could be garbage collected while the dict is reused.
+//
+// The memory of the keys set from bytes is reused,
+// so they must be copied to be retained after a reset.
 func (d *Dict) Reset() {
 	d.reset()
 }
 
+// Range calls fn sequentially for each key and value present in the dict.
+// If fn returns false, range stops the iteration.
+//
+// It's safe to delete the current key from fn.
+func (d *Dict) Range(fn func(key string, value interface{}) bool) {
+	d.rangeKV(fn)
+}
+
+// Keys appends the keys of the dict to dst and returns the extended slice.
+func (d *Dict) Keys(dst []string) []string {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	for i := range d.D {
+		dst = append(dst, d.D[i].Key)
+	}
+
+	return dst
+}
+
+// Values appends the values of the dict to dst and returns the extended slice.
+func (d *Dict) Values(dst []interface{}) []interface{} {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	for i := range d.D {
+		dst = append(dst, d.D[i].Value)
+	}
+
+	return dst
+}
+
+// At returns the key and value at the position i.
+//
+// It panics if i is out of range.
+func (d *Dict) At(i int) (string, interface{}) {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	kv := &d.D[i]
+
+	return kv.Key, kv.Value
+}
+
 // Map convert to map.
 func (d *Dict) Map(dst DictMap) {
 	for i := range d.D {
@@ -174,17 +670,8 @@ func (d *Dict) Map(dst DictMap) {
 }
 
 // Parse convert map to Dict.
+//
+// The nested maps with string keys are converted to nested dicts acquired from the pool.
 func (d *Dict) Parse(src DictMap) {
-	d.reset()
-
-	for k, v := range src {
-		sv, ok := v.(map[string]interface{})
-		if ok {
-			subDict := new(Dict)
-			subDict.Parse(sv)
-			d.append(k, subDict)
-		} else {
-			d.append(k, v)
-		}
-	}
+	d.parseMap(src)
 }
@@ -0,0 +1,600 @@
+package dictpool
+
+import "github.com/tinylib/msgp/msgp"
+
+// The Dict msgp methods are maintained by hand (see `msgp:ignore Dict` in types.go)
+// so the decoders can keep the lookup invariants of the dict.
+//
+// A dict is encoded as a map of its keys to their values, in the order of D,
+// with the nested dicts encoded as nested maps. The maps are decoded to nested
+// dicts acquired from the pool.
+//
+// The decoders also read the legacy layout {"D": [{"Key": …, "Value": …}], "BinarySearch": …},
+// which is detected as a map of 2 keys whose first key is "D" with an array
+// and the second one is "BinarySearch" with a bool. The elements of the array
+// must be maps with a string "Key", otherwise ErrMalformedLegacy is returned.
+//
+// A dict with the same keys would be read as the legacy layout, so the encoders
+// write it in the legacy layout, which is decoded back to the same keys.
+
+const (
+	msgpFieldD            = "D"
+	msgpFieldBinarySearch = "BinarySearch"
+	msgpFieldKey          = "Key"
+	msgpFieldValue        = "Value"
+	msgpLegacyFields      = 2
+)
+
+// DecodeMsg implements msgp.Decodable.
+func (d *Dict) DecodeMsg(dc *msgp.Reader) error {
+	sz, err := dc.ReadMapHeader()
+	if err != nil {
+		return msgp.WrapError(err)
+	}
+
+	return d.decodeMsgMap(dc, sz)
+}
+
+func (d *Dict) decodeMsgMap(dc *msgp.Reader, sz uint32) error {
+	d.reload()
+
+	for i := uint32(0); i < sz; i++ {
+		field, err := dc.ReadMapKeyPtr()
+		if err != nil {
+			return msgp.WrapError(err)
+		}
+
+		key := string(field)
+
+		value, owned, err := d.decodeMsgValue(dc)
+		if err != nil {
+			return msgp.WrapError(err, key)
+		}
+
+		if i == 0 && sz == msgpLegacyFields && key == msgpFieldD {
+			return d.decodeMsgLegacy(dc, value, owned)
+		}
+
+		d.appendLoaded(key, value).owned = owned
+	}
+
+	d.loaded()
+
+	return nil
+}
+
+// decodeMsgLegacy reads the second key of a map of 2 keys whose first key is "D"
+// with the given value, loading the dict from the legacy layout if it matches
+// or from both keys otherwise.
+func (d *Dict) decodeMsgLegacy(dc *msgp.Reader, value interface{}, owned bool) error {
+	field, err := dc.ReadMapKeyPtr()
+	if err != nil {
+		return msgp.WrapError(err)
+	}
+
+	key := string(field)
+
+	if elems, ok := value.([]interface{}); ok && key == msgpFieldBinarySearch {
+		if t, err := dc.NextType(); err == nil && t == msgp.BoolType {
+			if d.BinarySearch, err = dc.ReadBool(); err != nil {
+				releaseValue(elems)
+
+				return msgp.WrapError(err, msgpFieldBinarySearch)
+			}
+
+			return d.loadLegacy(elems)
+		}
+	}
+
+	second, secondOwned, err := d.decodeMsgValue(dc)
+	if err != nil {
+		if owned {
+			releaseValue(value)
+		}
+
+		return msgp.WrapError(err, key)
+	}
+
+	d.appendLoaded(msgpFieldD, value).owned = owned
+	d.appendLoaded(key, second).owned = secondOwned
+	d.loaded()
+
+	return nil
+}
+
+// decodeMsgValue decodes the next value, reporting whether the result
+// holds dicts owned by the dict.
+func (d *Dict) decodeMsgValue(dc *msgp.Reader) (interface{}, bool, error) {
+	t, err := dc.NextType()
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch t { // nolint:exhaustive
+	case msgp.MapType:
+		sz, err := dc.ReadMapHeader()
+		if err != nil {
+			return nil, false, err
+		}
+
+		sub := d.newChild()
+
+		if err := sub.decodeMsgMap(dc, sz); err != nil {
+			ReleaseDictDeep(sub)
+
+			return nil, false, err
+		}
+
+		return sub, true, nil
+	case msgp.ArrayType:
+		n, err := dc.ReadArrayHeader()
+		if err != nil {
+			return nil, false, err
+		}
+
+		values := make([]interface{}, 0, n)
+		owned := false
+
+		for i := uint32(0); i < n; i++ {
+			value, valueOwned, err := d.decodeMsgValue(dc)
+			if err != nil {
+				releaseValue(values)
+
+				return nil, false, msgp.WrapError(err, int(i))
+			}
+
+			values = append(values, value)
+			owned = owned || valueOwned
+		}
+
+		return values, owned, nil
+	}
+
+	value, err := dc.ReadIntf()
+
+	return value, false, err
+}
+
+// loadLegacy fills the dict with the decoded elements of the legacy layout,
+// taking the ownership of their nested dicts.
+func (d *Dict) loadLegacy(elems []interface{}) error {
+	var err error
+
+	for i, e := range elems {
+		kv, key, ok := legacyKV(e)
+		if !ok {
+			releaseValue(elems[i:])
+			err = msgp.WrapError(ErrMalformedLegacy, msgpFieldD, i)
+
+			break
+		}
+
+		owned := false
+
+		var value interface{}
+
+		if idx := kv.indexOf(msgpFieldValue); idx > -1 {
+			value, owned = kv.D[idx].Value, kv.D[idx].owned
+			kv.D[idx].owned = false
+		}
+
+		d.appendLoaded(key, value).owned = owned
+		ReleaseDictDeep(kv)
+	}
+
+	d.loaded()
+
+	return err
+}
+
+// legacyKV returns the decoded element of the legacy layout and its key,
+// or false if it's malformed.
+func legacyKV(e interface{}) (*Dict, string, bool) {
+	kv, ok := e.(*Dict)
+	if !ok {
+		return nil, "", false
+	}
+
+	key, ok := kv.Get(msgpFieldKey).(string)
+
+	return kv, key, ok
+}
+
+// legacyShaped reports whether the dict would be read as the legacy layout.
+func (d *Dict) legacyShaped() bool {
+	if d.len() != msgpLegacyFields || d.D[0].Key != msgpFieldD || d.D[1].Key != msgpFieldBinarySearch {
+		return false
+	}
+
+	_, ok := d.D[1].Value.(bool)
+
+	return ok
+}
+
+// EncodeMsg implements msgp.Encodable.
+func (d *Dict) EncodeMsg(en *msgp.Writer) error {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	if d.legacyShaped() {
+		return d.encodeMsgLegacy(en)
+	}
+
+	if err := en.WriteMapHeader(uint32(d.len())); err != nil {
+		return msgp.WrapError(err)
+	}
+
+	for i := range d.D {
+		kv := &d.D[i]
+
+		if err := en.WriteString(kv.Key); err != nil {
+			return msgp.WrapError(err)
+		}
+
+		if err := encodeMsgValue(en, kv.Value); err != nil {
+			return msgp.WrapError(err, kv.Key)
+		}
+	}
+
+	return nil
+}
+
+// encodeMsgLegacy encodes the dict in the legacy layout.
+func (d *Dict) encodeMsgLegacy(en *msgp.Writer) error {
+	if err := en.WriteMapHeader(msgpLegacyFields); err != nil {
+		return msgp.WrapError(err)
+	}
+
+	if err := en.WriteString(msgpFieldD); err != nil {
+		return msgp.WrapError(err)
+	}
+
+	if err := en.WriteArrayHeader(uint32(d.len())); err != nil {
+		return msgp.WrapError(err, msgpFieldD)
+	}
+
+	for i := range d.D {
+		kv := &d.D[i]
+
+		err := en.WriteMapHeader(msgpLegacyFields)
+		if err == nil {
+			err = en.WriteString(msgpFieldKey)
+		}
+
+		if err == nil {
+			err = en.WriteString(kv.Key)
+		}
+
+		if err == nil {
+			err = en.WriteString(msgpFieldValue)
+		}
+
+		if err == nil {
+			err = encodeMsgValue(en, kv.Value)
+		}
+
+		if err != nil {
+			return msgp.WrapError(err, msgpFieldD, i)
+		}
+	}
+
+	if err := en.WriteString(msgpFieldBinarySearch); err != nil {
+		return msgp.WrapError(err)
+	}
+
+	if err := en.WriteBool(d.BinarySearch); err != nil {
+		return msgp.WrapError(err, msgpFieldBinarySearch)
+	}
+
+	return nil
+}
+
+func encodeMsgValue(en *msgp.Writer, v interface{}) error {
+	switch val := v.(type) {
+	case *Dict:
+		if val == nil {
+			return en.WriteNil()
+		}
+
+		return val.EncodeMsg(en)
+	case []*Dict:
+		if err := en.WriteArrayHeader(uint32(len(val))); err != nil {
+			return err
+		}
+
+		for i, sd := range val {
+			if err := encodeMsgValue(en, sd); err != nil {
+				return msgp.WrapError(err, i)
+			}
+		}
+
+		return nil
+	case []interface{}:
+		if err := en.WriteArrayHeader(uint32(len(val))); err != nil {
+			return err
+		}
+
+		for i, e := range val {
+			if err := encodeMsgValue(en, e); err != nil {
+				return msgp.WrapError(err, i)
+			}
+		}
+
+		return nil
+	}
+
+	return en.WriteIntf(v)
+}
+
+// MarshalMsg implements msgp.Marshaler.
+func (d *Dict) MarshalMsg(b []byte) ([]byte, error) {
+	return d.appendMsg(msgp.Require(b, d.Msgsize()))
+}
+
+func (d *Dict) appendMsg(o []byte) ([]byte, error) {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	if d.legacyShaped() {
+		return d.appendMsgLegacy(o)
+	}
+
+	var err error
+
+	o = msgp.AppendMapHeader(o, uint32(d.len()))
+
+	for i := range d.D {
+		kv := &d.D[i]
+
+		o = msgp.AppendString(o, kv.Key)
+
+		if o, err = appendMsgValue(o, kv.Value); err != nil {
+			return o, msgp.WrapError(err, kv.Key)
+		}
+	}
+
+	return o, nil
+}
+
+// appendMsgLegacy is like encodeMsgLegacy for MarshalMsg.
+func (d *Dict) appendMsgLegacy(o []byte) ([]byte, error) {
+	var err error
+
+	o = msgp.AppendMapHeader(o, msgpLegacyFields)
+	o = msgp.AppendString(o, msgpFieldD)
+	o = msgp.AppendArrayHeader(o, uint32(d.len()))
+
+	for i := range d.D {
+		kv := &d.D[i]
+
+		o = msgp.AppendMapHeader(o, msgpLegacyFields)
+		o = msgp.AppendString(o, msgpFieldKey)
+		o = msgp.AppendString(o, kv.Key)
+		o = msgp.AppendString(o, msgpFieldValue)
+
+		if o, err = appendMsgValue(o, kv.Value); err != nil {
+			return o, msgp.WrapError(err, msgpFieldD, i)
+		}
+	}
+
+	o = msgp.AppendString(o, msgpFieldBinarySearch)
+
+	return msgp.AppendBool(o, d.BinarySearch), nil
+}
+
+func appendMsgValue(o []byte, v interface{}) ([]byte, error) {
+	var err error
+
+	switch val := v.(type) {
+	case *Dict:
+		if val == nil {
+			return msgp.AppendNil(o), nil
+		}
+
+		return val.appendMsg(o)
+	case []*Dict:
+		o = msgp.AppendArrayHeader(o, uint32(len(val)))
+
+		for i, sd := range val {
+			if o, err = appendMsgValue(o, sd); err != nil {
+				return o, msgp.WrapError(err, i)
+			}
+		}
+
+		return o, nil
+	case []interface{}:
+		o = msgp.AppendArrayHeader(o, uint32(len(val)))
+
+		for i, e := range val {
+			if o, err = appendMsgValue(o, e); err != nil {
+				return o, msgp.WrapError(err, i)
+			}
+		}
+
+		return o, nil
+	}
+
+	return msgp.AppendIntf(o, v)
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler.
+func (d *Dict) UnmarshalMsg(bts []byte) ([]byte, error) {
+	sz, bts, err := msgp.ReadMapHeaderBytes(bts)
+	if err != nil {
+		return bts, msgp.WrapError(err)
+	}
+
+	return d.unmarshalMsgMap(bts, sz)
+}
+
+func (d *Dict) unmarshalMsgMap(bts []byte, sz uint32) ([]byte, error) {
+	var (
+		field []byte
+		value interface{}
+		owned bool
+		err   error
+	)
+
+	d.reload()
+
+	for i := uint32(0); i < sz; i++ {
+		if field, bts, err = msgp.ReadMapKeyZC(bts); err != nil {
+			return bts, msgp.WrapError(err)
+		}
+
+		key := string(field)
+
+		if value, owned, bts, err = d.unmarshalMsgValue(bts); err != nil {
+			return bts, msgp.WrapError(err, key)
+		}
+
+		if i == 0 && sz == msgpLegacyFields && key == msgpFieldD {
+			return d.unmarshalMsgLegacy(bts, value, owned)
+		}
+
+		d.appendLoaded(key, value).owned = owned
+	}
+
+	d.loaded()
+
+	return bts, nil
+}
+
+// unmarshalMsgLegacy is like decodeMsgLegacy for UnmarshalMsg.
+func (d *Dict) unmarshalMsgLegacy(bts []byte, value interface{}, owned bool) ([]byte, error) {
+	field, bts, err := msgp.ReadMapKeyZC(bts)
+	if err != nil {
+		return bts, msgp.WrapError(err)
+	}
+
+	key := string(field)
+
+	if elems, ok := value.([]interface{}); ok && key == msgpFieldBinarySearch && msgp.NextType(bts) == msgp.BoolType {
+		if d.BinarySearch, bts, err = msgp.ReadBoolBytes(bts); err != nil {
+			releaseValue(elems)
+
+			return bts, msgp.WrapError(err, msgpFieldBinarySearch)
+		}
+
+		return bts, d.loadLegacy(elems)
+	}
+
+	second, secondOwned, bts, err := d.unmarshalMsgValue(bts)
+	if err != nil {
+		if owned {
+			releaseValue(value)
+		}
+
+		return bts, msgp.WrapError(err, key)
+	}
+
+	d.appendLoaded(msgpFieldD, value).owned = owned
+	d.appendLoaded(key, second).owned = secondOwned
+	d.loaded()
+
+	return bts, nil
+}
+
+// unmarshalMsgValue is like decodeMsgValue for UnmarshalMsg.
+func (d *Dict) unmarshalMsgValue(bts []byte) (interface{}, bool, []byte, error) {
+	var err error
+
+	switch msgp.NextType(bts) { // nolint:exhaustive
+	case msgp.MapType:
+		var sz uint32
+
+		if sz, bts, err = msgp.ReadMapHeaderBytes(bts); err != nil {
+			return nil, false, bts, err
+		}
+
+		sub := d.newChild()
+
+		if bts, err = sub.unmarshalMsgMap(bts, sz); err != nil {
+			ReleaseDictDeep(sub)
+
+			return nil, false, bts, err
+		}
+
+		return sub, true, bts, nil
+	case msgp.ArrayType:
+		var n uint32
+
+		if n, bts, err = msgp.ReadArrayHeaderBytes(bts); err != nil {
+			return nil, false, bts, err
+		}
+
+		values := make([]interface{}, 0, n)
+		owned := false
+
+		for i := uint32(0); i < n; i++ {
+			var (
+				value      interface{}
+				valueOwned bool
+			)
+
+			if value, valueOwned, bts, err = d.unmarshalMsgValue(bts); err != nil {
+				releaseValue(values)
+
+				return nil, false, bts, msgp.WrapError(err, int(i))
+			}
+
+			values = append(values, value)
+			owned = owned || valueOwned
+		}
+
+		return values, owned, bts, nil
+	}
+
+	value, bts, err := msgp.ReadIntfBytes(bts)
+
+	return value, false, bts, err
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message.
+func (d *Dict) Msgsize() int {
+	s := msgp.MapHeaderSize
+
+	for i := range d.D {
+		kv := &d.D[i]
+		s += msgp.StringPrefixSize + len(kv.Key) + msgsizeValue(kv.Value)
+	}
+
+	if d.legacyShaped() {
+		s += msgp.StringPrefixSize + len(msgpFieldD) + msgp.ArrayHeaderSize +
+			msgp.StringPrefixSize + len(msgpFieldBinarySearch) + msgp.BoolSize +
+			msgpLegacyFields*(msgp.MapHeaderSize+2*msgp.StringPrefixSize+len(msgpFieldKey)+len(msgpFieldValue))
+	}
+
+	return s
+}
+
+func msgsizeValue(v interface{}) int {
+	switch val := v.(type) {
+	case *Dict:
+		if val == nil {
+			return msgp.NilSize
+		}
+
+		return val.Msgsize()
+	case []*Dict:
+		s := msgp.ArrayHeaderSize
+
+		for _, sd := range val {
+			s += msgsizeValue(sd)
+		}
+
+		return s
+	case []interface{}:
+		s := msgp.ArrayHeaderSize
+
+		for _, e := range val {
+			s += msgsizeValue(e)
+		}
+
+		return s
+	}
+
+	return msgp.GuessSize(v)
+}
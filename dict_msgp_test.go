@@ -0,0 +1,441 @@
+package dictpool
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+func TestMarshalUnmarshalDict(t *testing.T) {
+	v := Dict{}
+	bts, err := v.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func BenchmarkMarshalMsgDict(b *testing.B) {
+	v := Dict{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgDict(b *testing.B) {
+	v := Dict{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts, _ = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts, _ = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalDict(b *testing.B) {
+	v := Dict{}
+	bts, _ := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodeDecodeDict(t *testing.T) {
+	v := Dict{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+
+	m := v.Msgsize()
+	if buf.Len() > m {
+		t.Log("WARNING: TestEncodeDecodeDict Msgsize() is inaccurate")
+	}
+
+	vn := Dict{}
+	err := msgp.Decode(&buf, &vn)
+	if err != nil {
+		t.Error(err)
+	}
+
+	buf.Reset()
+	msgp.Encode(&buf, &v)
+	err = msgp.NewReader(&buf).Skip()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncodeDict(b *testing.B) {
+	v := Dict{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	en := msgp.NewWriter(msgp.Nowhere)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.EncodeMsg(en)
+	}
+	en.Flush()
+}
+
+func BenchmarkDecodeDict(b *testing.B) {
+	v := Dict{}
+	var buf bytes.Buffer
+	msgp.Encode(&buf, &v)
+	b.SetBytes(int64(buf.Len()))
+	rd := msgp.NewEndlessReader(buf.Bytes(), b)
+	dc := msgp.NewReader(rd)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := v.DecodeMsg(dc)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// appendLegacyDict appends the dict in the legacy layout.
+func appendLegacyDict(b []byte, d *Dict) []byte {
+	b = msgp.AppendMapHeader(b, 2)
+	b = msgp.AppendString(b, msgpFieldD)
+	b = msgp.AppendArrayHeader(b, uint32(d.Len()))
+
+	for _, kv := range d.D {
+		b, _ = kv.MarshalMsg(b)
+	}
+
+	b = msgp.AppendString(b, msgpFieldBinarySearch)
+
+	return msgp.AppendBool(b, d.BinarySearch)
+}
+
+func TestDict_DecodeMsgLegacy(t *testing.T) {
+	src := AcquireDict()
+	keys := []string{"c", "a", "d", "b"}
+
+	for i, k := range keys {
+		src.Set(k, int64(i))
+	}
+
+	src.Set("sub", map[string]interface{}{"key": "value"})
+	src.BinarySearch = true
+
+	bts := appendLegacyDict(nil, src)
+
+	d1 := AcquireDict()
+	if _, err := d1.UnmarshalMsg(bts); err != nil {
+		t.Fatal(err)
+	}
+
+	d2 := AcquireDict()
+	if err := msgp.Decode(bytes.NewReader(bts), d2); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []*Dict{d1, d2} {
+		if !d.BinarySearch {
+			t.Error("BinarySearch has not been decoded")
+		}
+
+		for i, k := range keys {
+			if val := d.Get(k); val != int64(i) {
+				t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+			}
+		}
+
+		if sub, ok := d.GetDict("sub"); !ok || sub.Get("key") != "value" {
+			t.Errorf("Dict.Get() = '%v', want a nested dict", d.Get("sub"))
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("Dict.Validate() unexpected error: %v", err)
+		}
+
+		d.BinarySearch = false
+		ReleaseDictDeep(d)
+	}
+
+	src.BinarySearch = false
+	ReleaseDict(src)
+}
+
+func TestDict_DecodeMsgNested(t *testing.T) {
+	sub := AcquireDict()
+	sub.Set("z", int64(1))
+	sub.Set("a", "b")
+
+	src := AcquireDict()
+	src.Set("foo", "bar")
+	src.Set("sub", sub)
+	src.Set("list", []interface{}{int64(1), sub})
+	src.Set("dicts", []*Dict{sub})
+
+	bts, err := src.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := src.Msgsize(); len(bts) > n {
+		t.Errorf("Dict.Msgsize() = %d, want at least %d", n, len(bts))
+	}
+
+	var buf bytes.Buffer
+
+	if err := msgp.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), bts) {
+		t.Errorf("Dict.EncodeMsg() = %x, want %x", buf.Bytes(), bts)
+	}
+
+	d1 := AcquireDict()
+	if _, err := d1.UnmarshalMsg(bts); err != nil {
+		t.Fatal(err)
+	}
+
+	d2 := AcquireDict()
+	if err := msgp.Decode(&buf, d2); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"foo":"bar","sub":{"z":1,"a":"b"},"list":[1,{"z":1,"a":"b"}],"dicts":[{"z":1,"a":"b"}]}`
+
+	for _, d := range []*Dict{d1, d2} {
+		if got := string(d.AppendJSON(nil)); got != want {
+			t.Errorf("Dict.DecodeMsg() = %s, want %s", got, want)
+		}
+
+		decoded, _ := d.GetDict("sub")
+
+		ReleaseDictDeep(d)
+
+		if decoded.Len() != 0 {
+			t.Error("ReleaseDictDeep() decoded nested dict not released")
+		}
+	}
+
+	ReleaseDict(src)
+	ReleaseDict(sub)
+}
+
+func TestDict_DecodeMsgLookup(t *testing.T) {
+	src := AcquireDict()
+	keys := []string{"c", "a", "d", "b"}
+
+	for i, k := range keys {
+		src.Set(k, int64(i))
+	}
+
+	bts, err := src.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := AcquireDict()
+	d.D = make([]KV, 0, 16)
+	d.SetBinarySearch(true)
+	d.SetHashIndex(true)
+
+	capacity := cap(d.D)
+
+	if _, err := d.UnmarshalMsg(bts); err != nil {
+		t.Fatal(err)
+	}
+
+	if cap(d.D) != capacity {
+		t.Errorf("Dict.UnmarshalMsg() cap = %d, want %d", cap(d.D), capacity)
+	}
+
+	if !d.BinarySearch || !d.hashed {
+		t.Error("Dict.UnmarshalMsg() the lookup strategy has been changed")
+	}
+
+	for i, k := range keys {
+		if val := d.Get(k); val != int64(i) {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+
+	d.SetHashIndex(false)
+	d.SetBinarySearch(false)
+	ReleaseDict(d)
+	ReleaseDict(src)
+}
+
+func TestDict_DecodeMsgNotLegacy(t *testing.T) {
+	// A nested dict keeps the order of its keys, so the encoding is deterministic.
+	elem := New()
+	elem.Set("Key", "key")
+	elem.Set("Value", int64(1))
+
+	tests := []DictMap{
+		{"D": []interface{}{int64(1)}, "BinarySearch": "no"},
+		{"D": []interface{}{int64(1)}, "other": true},
+		{"D": "value", "BinarySearch": true},
+		// The compact layout of these dicts is the legacy one, so they are written as legacy.
+		{"D": []interface{}{int64(1)}, "BinarySearch": true},
+		{"D": []interface{}{elem}, "BinarySearch": false},
+	}
+
+	for _, m := range tests {
+		src := AcquireDict()
+		src.Set("D", m["D"])
+
+		for k, v := range m {
+			if k != "D" {
+				src.Set(k, v)
+			}
+		}
+
+		bts, err := src.MarshalMsg(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(bts) > src.Msgsize() {
+			t.Errorf("Dict.Msgsize() = %d, want >= %d", src.Msgsize(), len(bts))
+		}
+
+		var buf bytes.Buffer
+
+		if err := msgp.Encode(&buf, src); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf.Bytes(), bts) {
+			t.Errorf("Dict.EncodeMsg() = %x, want %x", buf.Bytes(), bts)
+		}
+
+		d1 := AcquireDict()
+		if _, err := d1.UnmarshalMsg(bts); err != nil {
+			t.Fatal(err)
+		}
+
+		d2 := AcquireDict()
+		if err := msgp.Decode(bytes.NewReader(bts), d2); err != nil {
+			t.Fatal(err)
+		}
+
+		want := string(src.AppendJSON(nil))
+
+		for _, d := range []*Dict{d1, d2} {
+			if got := string(d.AppendJSON(nil)); got != want {
+				t.Errorf("Dict.DecodeMsg() = %s, want %s", got, want)
+			}
+
+			if d.BinarySearch {
+				t.Error("Dict.DecodeMsg() decoded as the legacy layout")
+			}
+
+			ReleaseDictDeep(d)
+		}
+
+		ReleaseDict(src)
+	}
+}
+
+func TestDict_DecodeMsgMalformedLegacy(t *testing.T) {
+	elems := map[string]func(b []byte) []byte{
+		"not map": func(b []byte) []byte {
+			return msgp.AppendInt64(b, 1)
+		},
+		"missing key": func(b []byte) []byte {
+			b = msgp.AppendMapHeader(b, 1)
+			b = msgp.AppendString(b, msgpFieldValue)
+
+			return msgp.AppendInt64(b, 1)
+		},
+		"key not string": func(b []byte) []byte {
+			b = msgp.AppendMapHeader(b, 1)
+			b = msgp.AppendString(b, msgpFieldKey)
+
+			return msgp.AppendInt64(b, 1)
+		},
+	}
+
+	for name, appendElem := range elems {
+		bts := msgp.AppendMapHeader(nil, 2)
+		bts = msgp.AppendString(bts, msgpFieldD)
+		bts = msgp.AppendArrayHeader(bts, 2)
+		bts = msgp.AppendMapHeader(bts, 2)
+		bts = msgp.AppendString(bts, msgpFieldKey)
+		bts = msgp.AppendString(bts, "key")
+		bts = msgp.AppendString(bts, msgpFieldValue)
+		bts = msgp.AppendMapHeader(bts, 1)
+		bts = msgp.AppendString(bts, "sub")
+		bts = msgp.AppendInt64(bts, 1)
+		bts = appendElem(bts)
+		bts = msgp.AppendString(bts, msgpFieldBinarySearch)
+		bts = msgp.AppendBool(bts, false)
+
+		d := AcquireDict()
+
+		if _, err := d.UnmarshalMsg(bts); !errors.Is(err, ErrMalformedLegacy) {
+			t.Errorf("%s: Dict.UnmarshalMsg() error = '%v', want '%v'", name, err, ErrMalformedLegacy)
+		}
+
+		if err := msgp.Decode(bytes.NewReader(bts), d); !errors.Is(err, ErrMalformedLegacy) {
+			t.Errorf("%s: Dict.DecodeMsg() error = '%v', want '%v'", name, err, ErrMalformedLegacy)
+		}
+
+		ReleaseDictDeep(d)
+	}
+}
+
+func TestDict_DecodeMsgErrors(t *testing.T) {
+	src := AcquireDict()
+	src.Set("D", []interface{}{int64(1)})
+	src.Set("sub", map[string]interface{}{"key": []interface{}{"value"}})
+
+	bts, err := src.MarshalMsg(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := AcquireDict()
+
+	for i := 0; i < len(bts); i++ {
+		if _, err := d.UnmarshalMsg(bts[:i]); err == nil {
+			t.Errorf("Dict.UnmarshalMsg() expected an error with %d bytes", i)
+		}
+
+		if err := msgp.Decode(bytes.NewReader(bts[:i]), d); err == nil {
+			t.Errorf("Dict.DecodeMsg() expected an error with %d bytes", i)
+		}
+	}
+
+	ReleaseDict(d)
+	ReleaseDictDeep(src)
+}
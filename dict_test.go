@@ -1,7 +1,9 @@
 package dictpool
 
 import (
+	"errors"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 
@@ -125,6 +127,100 @@ func TestDict_GetBytes(t *testing.T) {
 	}
 }
 
+func TestDict_Lookup(t *testing.T) {
+	d := AcquireDict()
+	d.Set("nil", nil)
+	d.Set("key", "value")
+
+	if v, ok := d.Lookup("nil"); !ok || v != nil {
+		t.Errorf("Dict.Lookup() = (%v, %v), want (%v, %v)", v, ok, nil, true)
+	}
+
+	if v, ok := d.LookupBytes([]byte("key")); !ok || v != "value" {
+		t.Errorf("Dict.LookupBytes() = (%v, %v), want (%v, %v)", v, ok, "value", true)
+	}
+
+	if v, ok := d.Lookup("missing"); ok || v != nil {
+		t.Errorf("Dict.Lookup() = (%v, %v), want (%v, %v)", v, ok, nil, false)
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_GetOrSet(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+		d.Set("b", 1)
+
+		if v, loaded := d.GetOrSet("b", 2); !loaded || v != 1 {
+			t.Errorf("Dict.GetOrSet() = (%v, %v), want (%v, %v)", v, loaded, 1, true)
+		}
+
+		if v, loaded := d.GetOrSetBytes([]byte("a"), 3); loaded || v != 3 {
+			t.Errorf("Dict.GetOrSetBytes() = (%v, %v), want (%v, %v)", v, loaded, 3, false)
+		}
+
+		if !d.SetIfAbsent("c", 4) || d.SetIfAbsentBytes([]byte("c"), 5) {
+			t.Error("Dict.SetIfAbsent() has set an existing key")
+		}
+
+		if v := d.Get("c"); v != 4 {
+			t.Errorf("Dict.Get() = '%v', want '%v'", v, 4)
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("Dict.Validate() unexpected error: %v", err)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_SwapValue(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+		d.Set("b", 1)
+
+		if v, loaded := d.SwapValue("b", 2); !loaded || v != 1 {
+			t.Errorf("Dict.SwapValue() = (%v, %v), want (%v, %v)", v, loaded, 1, true)
+		}
+
+		if v, loaded := d.SwapValueBytes([]byte("a"), 3); loaded || v != nil {
+			t.Errorf("Dict.SwapValueBytes() = (%v, %v), want (%v, %v)", v, loaded, nil, false)
+		}
+
+		if d.Get("a") != 3 || d.Get("b") != 2 {
+			t.Errorf("Dict.SwapValue() has not been set the values: %v", d.D)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_LoadAndDelete(t *testing.T) {
+	d := AcquireDict()
+	d.Set("a", 1)
+	d.Set("b", 2)
+
+	if v, loaded := d.LoadAndDelete("a"); !loaded || v != 1 {
+		t.Errorf("Dict.LoadAndDelete() = (%v, %v), want (%v, %v)", v, loaded, 1, true)
+	}
+
+	if v, loaded := d.LoadAndDeleteBytes([]byte("a")); loaded || v != nil {
+		t.Errorf("Dict.LoadAndDeleteBytes() = (%v, %v), want (%v, %v)", v, loaded, nil, false)
+	}
+
+	if d.Has("a") || !d.Has("b") {
+		t.Error("Dict.LoadAndDelete() has not been deleted the key")
+	}
+
+	ReleaseDict(d)
+}
+
 func TestDict_Set(t *testing.T) {
 	const k, v = "key", "value"
 
@@ -144,6 +240,101 @@ func TestDict_Set(t *testing.T) {
 	}
 }
 
+func TestDict_SetSorted(t *testing.T) {
+	d := AcquireDict()
+	d.BinarySearch = true
+
+	keys := genKeys(t, 100)
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	if !sort.IsSorted(d) {
+		t.Fatal("Dict.Set() the dict is not sorted")
+	}
+
+	for i, k := range keys {
+		if val := d.Get(k); val != i {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	capacity := cap(d.D)
+
+	d.Reset()
+
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	if cap(d.D) != capacity {
+		t.Errorf("Dict.Set() cap == %d, want %d", cap(d.D), capacity)
+	}
+}
+
+func TestDict_SetBinarySearch(t *testing.T) {
+	d := AcquireDict()
+
+	keys := genKeys(t, 50)
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	d.SetBinarySearch(true)
+
+	for i, k := range keys {
+		if val := d.Get(k); val != i {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	if !sort.IsSorted(d) {
+		t.Error("Dict.SetBinarySearch() the dict is not sorted")
+	}
+
+	d.SetBinarySearch(false)
+	d.Set("zzz", true)
+	d.Set("000", true)
+
+	// Switching through the exported field must behave the same way.
+	d.BinarySearch = true
+
+	if !d.Has("000") || !d.Has("zzz") {
+		t.Error("Dict.Has() returns false for present keys after enabling the binary search")
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+}
+
+func TestDict_Validate(t *testing.T) {
+	d := AcquireDict()
+	d.Set("b", 1)
+	d.Set("a", 2)
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+
+	d.D = append(d.D, KV{Key: "b", Value: 3})
+
+	if err := d.Validate(); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Dict.Validate() = %v, want %v", err, ErrDuplicateKey)
+	}
+
+	d.Reset()
+	d.SetBinarySearch(true)
+	d.Set("a", 1)
+	d.Set("b", 2)
+
+	d.swap(0, 1)
+
+	if err := d.Validate(); !errors.Is(err, ErrUnsortedKeys) {
+		t.Errorf("Dict.Validate() = %v, want %v", err, ErrUnsortedKeys)
+	}
+}
+
 func TestDict_SetBytes(t *testing.T) {
 	const v = "value"
 
@@ -225,6 +416,173 @@ func TestDict_Reset(t *testing.T) {
 	if len(d.D) > 0 {
 		t.Error("Dict.Reset() the length of Dict is not 0")
 	}
+
+	for i, kv := range d.D[:cap(d.D)] {
+		if kv.Key != "" || kv.Value != nil {
+			t.Errorf("Dict.Reset() stale reference at index %d: %v", i, kv)
+		}
+	}
+}
+
+func TestDict_DelClear(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		d.Set("a", 1)
+		d.Set("b", 2)
+		d.Set("c", 3)
+		d.Del("a")
+
+		if kv := d.D[:cap(d.D)][d.Len()]; kv.Key != "" || kv.Value != nil {
+			t.Errorf("Dict.Del() stale reference in the vacated slot: %v", kv)
+		}
+
+		if d.Get("b") != 2 || d.Get("c") != 3 {
+			t.Errorf("Dict.Del() moved values: %v", d.D)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_Range(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		keys := []string{"a", "b", "c", "d"}
+		for i, k := range keys {
+			d.Set(k, i)
+		}
+
+		var got []string
+
+		d.Range(func(key string, value interface{}) bool {
+			got = append(got, key)
+
+			if value != d.Get(key) {
+				t.Errorf("Dict.Range() value = '%v', want '%v'", value, d.Get(key))
+			}
+
+			// Deleting the current key must not skip the next one.
+			if key == "b" {
+				d.Del(key)
+			}
+
+			return key != "c"
+		})
+
+		if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Dict.Range() keys == %v, want %v", got, want)
+		}
+
+		if d.Has("b") {
+			t.Error("Dict.Range() key not deleted")
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_Keys(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		d.Set("b", 1)
+		d.Set("a", 2)
+
+		want := []string{"b", "a"}
+		if binary {
+			want = []string{"a", "b"}
+		}
+
+		dst := make([]string, 0, 2)
+
+		if got := d.Keys(dst); !reflect.DeepEqual(got, want) {
+			t.Errorf("Dict.Keys() == %v, want %v", got, want)
+		}
+
+		if got := d.Keys([]string{"c"}); !reflect.DeepEqual(got, append([]string{"c"}, want...)) {
+			t.Errorf("Dict.Keys() has not been appended to dst: %v", got)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_Values(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		d.Set("b", 1)
+		d.Set("a", 2)
+
+		want := []interface{}{1, 2}
+		if binary {
+			want = []interface{}{2, 1}
+		}
+
+		if got := d.Values(nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("Dict.Values() == %v, want %v", got, want)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_At(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		d.Set("b", 1)
+		d.Set("a", 2)
+
+		wantKey, wantValue := "b", 1
+		if binary {
+			wantKey, wantValue = "a", 2
+		}
+
+		if key, value := d.At(0); key != wantKey || value != wantValue {
+			t.Errorf("Dict.At() == (%q, %v), want (%q, %v)", key, value, wantKey, wantValue)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_IterAllocs(t *testing.T) {
+	d := AcquireDict()
+
+	for _, k := range []string{"a", "b", "c"} {
+		d.Set(k, true)
+	}
+
+	keys := make([]string, 0, d.Len())
+	values := make([]interface{}, 0, d.Len())
+
+	allocs := testing.AllocsPerRun(100, func() {
+		keys = d.Keys(keys[:0])
+		values = d.Values(values[:0])
+
+		d.Range(func(key string, value interface{}) bool {
+			return true
+		})
+	})
+
+	if allocs != 0 {
+		t.Errorf("Dict iteration allocs == %v, want 0", allocs)
+	}
+
+	ReleaseDict(d)
 }
 
 func TestDict_Map(t *testing.T) {
@@ -294,6 +652,27 @@ func TestDict_Parse(t *testing.T) {
 	}
 }
 
+func TestDict_ParseBinarySearch(t *testing.T) {
+	m := make(DictMap)
+	for i, k := range genKeys(t, 50) {
+		m[k] = i
+	}
+
+	d := AcquireDict()
+	d.SetBinarySearch(true)
+	d.Parse(m)
+
+	for k, v := range m {
+		if val := d.Get(k); val != v {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, v)
+		}
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+}
+
 func genKeys(tb testing.TB, size int) []string {
 	tb.Helper()
 
@@ -387,6 +766,59 @@ func Benchmark_SetBinaryBigHeap(b *testing.B) {
 	benchmarkSet(b, d, 1000)
 }
 
+func benchmarkBulkSet(b *testing.B, items int, resort bool) {
+	b.Helper()
+
+	keys := genKeys(b, items)
+
+	d := AcquireDict()
+	d.BinarySearch = true
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j, key := range keys {
+			if !resort {
+				d.Set(key, j)
+			} else if idx := d.indexOf(key); idx > -1 {
+				d.D[idx].Value = j
+			} else {
+				// Previous behaviour: append and sort the whole slice.
+				// The exported Swap marks the dict as unsorted, so sort by key.
+				d.append(key, j)
+				sort.Sort((*keySorter)(d))
+				d.sorted = true
+			}
+		}
+
+		d.Reset()
+	}
+}
+
+func Benchmark_SetBinaryBulk10(b *testing.B) {
+	benchmarkBulkSet(b, 10, false)
+}
+
+func Benchmark_SetBinaryBulk100(b *testing.B) {
+	benchmarkBulkSet(b, 100, false)
+}
+
+func Benchmark_SetBinaryBulk10k(b *testing.B) {
+	benchmarkBulkSet(b, 10000, false)
+}
+
+func Benchmark_SetBinaryBulkResort10(b *testing.B) {
+	benchmarkBulkSet(b, 10, true)
+}
+
+func Benchmark_SetBinaryBulkResort100(b *testing.B) {
+	benchmarkBulkSet(b, 100, true)
+}
+
+func Benchmark_SetBinaryBulkResort10k(b *testing.B) {
+	benchmarkBulkSet(b, 10000, true)
+}
+
 func benchmarkDel(b *testing.B, d *Dict, items int) {
 	b.Helper()
 
@@ -464,11 +896,10 @@ func Benchmark_Parse(b *testing.B) {
 	ReleaseDict(d)
 }
 
-func BenchmarkDict(b *testing.B) {
-	keys := genKeys(b, 100)
+func benchmarkDict(b *testing.B, u *Dict, items int) {
+	b.Helper()
 
-	u := AcquireDict()
-	// u.BinarySearch = true
+	keys := genKeys(b, items)
 
 	b.ResetTimer()
 
@@ -485,8 +916,37 @@ func BenchmarkDict(b *testing.B) {
 	}
 }
 
-func BenchmarkStdMap(b *testing.B) {
-	keys := genKeys(b, 100)
+func BenchmarkDict(b *testing.B) {
+	u := AcquireDict()
+	// u.BinarySearch = true
+
+	benchmarkDict(b, u, 100)
+}
+
+func BenchmarkDictAdaptive(b *testing.B) {
+	u := AcquireDict()
+	u.SetAdaptive(true)
+
+	benchmarkDict(b, u, 100)
+}
+
+func BenchmarkDictBigHeap(b *testing.B) {
+	u := AcquireDict()
+
+	benchmarkDict(b, u, 1000)
+}
+
+func BenchmarkDictAdaptiveBigHeap(b *testing.B) {
+	u := AcquireDict()
+	u.SetAdaptive(true)
+
+	benchmarkDict(b, u, 1000)
+}
+
+func benchmarkStdMap(b *testing.B, items int) {
+	b.Helper()
+
+	keys := genKeys(b, items)
 	u := make(map[string]interface{})
 
 	b.ResetTimer()
@@ -504,8 +964,18 @@ func BenchmarkStdMap(b *testing.B) {
 	}
 }
 
-func BenchmarkSyncMap(b *testing.B) {
-	keys := genKeys(b, 100)
+func BenchmarkStdMap(b *testing.B) {
+	benchmarkStdMap(b, 100)
+}
+
+func BenchmarkStdMapBigHeap(b *testing.B) {
+	benchmarkStdMap(b, 1000)
+}
+
+func benchmarkSyncMap(b *testing.B, items int) {
+	b.Helper()
+
+	keys := genKeys(b, items)
 	u := new(sync.Map)
 
 	b.ResetTimer()
@@ -526,3 +996,89 @@ func BenchmarkSyncMap(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkSyncMap(b *testing.B) {
+	benchmarkSyncMap(b, 100)
+}
+
+func BenchmarkSyncMapBigHeap(b *testing.B) {
+	benchmarkSyncMap(b, 1000)
+}
+
+func TestDict_SetBytesOwnedKey(t *testing.T) {
+	tests := map[string]func(d *Dict, key []byte){
+		"SetBytes":         func(d *Dict, key []byte) { d.SetBytes(key, "value") },
+		"GetOrSetBytes":    func(d *Dict, key []byte) { d.GetOrSetBytes(key, "value") },
+		"SetIfAbsentBytes": func(d *Dict, key []byte) { d.SetIfAbsentBytes(key, "value") },
+		"SwapValueBytes":   func(d *Dict, key []byte) { d.SwapValueBytes(key, "value") },
+	}
+
+	for name, set := range tests {
+		for _, binary := range []bool{false, true} {
+			d := AcquireDict()
+			d.SetBinarySearch(binary)
+
+			buf := []byte("foo")
+			set(d, buf)
+
+			// Reuse the buffer like fasthttp does.
+			copy(buf, "bar")
+			set(d, buf)
+
+			for _, key := range []string{"foo", "bar"} {
+				if v := d.Get(key); v != "value" {
+					t.Errorf("%s: Dict.Get(%q) = '%v', want '%v'", name, key, v, "value")
+				}
+			}
+
+			if err := d.Validate(); err != nil {
+				t.Errorf("%s: Dict.Validate() unexpected error: %v", name, err)
+			}
+
+			d.SetBinarySearch(false)
+			ReleaseDict(d)
+		}
+	}
+}
+
+func TestDict_SetBytesDelBytes(t *testing.T) {
+	d := AcquireDict()
+	d.SetBytes([]byte("live"), 1)
+
+	key := []byte("0123456789abcdef")
+
+	// The bytes of the deleted keys are not kept by the key arena.
+	for i := 0; i < 100000; i++ {
+		d.SetBytes(key, i)
+		d.DelBytes(key)
+	}
+
+	if cap(d.keys.buf) > minKeyArenaSize {
+		t.Errorf("Dict key arena capacity == %d, want <= %d", cap(d.keys.buf), minKeyArenaSize)
+	}
+
+	if v := d.Get("live"); v != 1 || d.Len() != 1 {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, 1)
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_SetBytesAllocs(t *testing.T) {
+	d := AcquireDict()
+	keys := [][]byte{[]byte("foo"), []byte("bar"), []byte("baz")}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, key := range keys {
+			d.SetBytes(key, nil)
+		}
+
+		d.Reset()
+	})
+
+	if allocs > 0 {
+		t.Errorf("Dict.SetBytes() allocs = %v, want 0", allocs)
+	}
+
+	ReleaseDict(d)
+}
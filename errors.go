@@ -0,0 +1,33 @@
+package dictpool
+
+import "errors"
+
+var (
+	// ErrDuplicateKey is returned by Dict.Validate when a key is stored more than once.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrUnsortedKeys is returned by Dict.Validate when a sorted dict has keys out of order.
+	ErrUnsortedKeys = errors.New("unsorted keys")
+
+	// ErrStaleIndex is returned by Dict.Validate when the hash index does not match D.
+	ErrStaleIndex = errors.New("stale hash index")
+
+	// ErrNotSorted is the panic of the ordered queries of a dict
+	// without binary search that is not adaptive.
+	ErrNotSorted = errors.New("dict is not sorted, enable the binary search or the adaptive strategy")
+
+	// ErrMalformedLegacy is returned by the msgp decoders of Dict when an element
+	// of the legacy layout is not a map with a string "Key".
+	ErrMalformedLegacy = errors.New("malformed legacy msgp element")
+
+	// ErrNotObject is returned by Dict.UnmarshalJSON when the JSON value is not an object.
+	ErrNotObject = errors.New("json value is not an object")
+
+	// ErrDoubleRelease is the panic of releasing a dict twice,
+	// only detected with the dictpool_debug build tag.
+	ErrDoubleRelease = errors.New("dict released twice")
+
+	// ErrUseAfterRelease is the panic of using a dict after releasing it,
+	// only detected with the dictpool_debug build tag.
+	ErrUseAfterRelease = errors.New("dict used after release")
+)
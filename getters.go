@@ -0,0 +1,242 @@
+package dictpool
+
+import (
+	"math"
+	"time"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+const (
+	maxInt64Float  = float64(1 << 63)
+	maxUint64Float = float64(1 << 64)
+)
+
+func getAs[T any](d *Dict, key string) (T, bool) {
+	v, ok := d.get(key).(T)
+
+	return v, ok
+}
+
+// toInt64 converts any integer or integral float to int64 if it fits.
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint, uint8, uint16, uint32, uint64:
+		u, _ := toUint64(n)
+		if u > math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(u), true
+	case float32:
+		return floatToInt64(float64(n))
+	case float64:
+		return floatToInt64(n)
+	}
+
+	return 0, false
+}
+
+// toUint64 converts any non negative integer or integral float to uint64 if it fits.
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case uint:
+		return uint64(n), true
+	case uint8:
+		return uint64(n), true
+	case uint16:
+		return uint64(n), true
+	case uint32:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	case int, int8, int16, int32, int64:
+		i, _ := toInt64(n)
+		if i < 0 {
+			return 0, false
+		}
+
+		return uint64(i), true
+	case float32:
+		return floatToUint64(float64(n))
+	case float64:
+		return floatToUint64(n)
+	}
+
+	return 0, false
+}
+
+// toFloat64 converts any integer or float to float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case uint, uint8, uint16, uint32, uint64:
+		u, _ := toUint64(n)
+
+		return float64(u), true
+	}
+
+	if i, ok := toInt64(v); ok {
+		return float64(i), true
+	}
+
+	return 0, false
+}
+
+func floatToInt64(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < -maxInt64Float || f >= maxInt64Float {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+func floatToUint64(f float64) (uint64, bool) {
+	if f != math.Trunc(f) || f < 0 || f >= maxUint64Float {
+		return 0, false
+	}
+
+	return uint64(f), true
+}
+
+// SetLenientNumbers enables or disables the conversion between numeric kinds
+// in GetInt, GetInt64, GetUint64 and GetFloat64.
+//
+// When enabled, a value is converted if it's representable by the target type,
+// e.g. float64(3) is returned by GetInt but float64(3.5) is not.
+func (d *Dict) SetLenientNumbers(enabled bool) {
+	d.lenientNumbers = enabled
+}
+
+// GetString returns the string value of the key and true,
+// or false if the key does not exist or its value is not a string.
+func (d *Dict) GetString(key string) (string, bool) {
+	return getAs[string](d, key)
+}
+
+// GetStringBytes is like GetString with the key as bytes.
+func (d *Dict) GetStringBytes(key []byte) (string, bool) {
+	return d.GetString(strconv.B2S(key))
+}
+
+// GetInt returns the int value of the key and true,
+// or false if the key does not exist or its value is not an int.
+func (d *Dict) GetInt(key string) (int, bool) {
+	if !d.lenientNumbers {
+		return getAs[int](d, key)
+	}
+
+	n, ok := toInt64(d.get(key))
+	if !ok || n < math.MinInt || n > math.MaxInt {
+		return 0, false
+	}
+
+	return int(n), true
+}
+
+// GetIntBytes is like GetInt with the key as bytes.
+func (d *Dict) GetIntBytes(key []byte) (int, bool) {
+	return d.GetInt(strconv.B2S(key))
+}
+
+// GetInt64 returns the int64 value of the key and true,
+// or false if the key does not exist or its value is not an int64.
+func (d *Dict) GetInt64(key string) (int64, bool) {
+	if !d.lenientNumbers {
+		return getAs[int64](d, key)
+	}
+
+	return toInt64(d.get(key))
+}
+
+// GetInt64Bytes is like GetInt64 with the key as bytes.
+func (d *Dict) GetInt64Bytes(key []byte) (int64, bool) {
+	return d.GetInt64(strconv.B2S(key))
+}
+
+// GetUint64 returns the uint64 value of the key and true,
+// or false if the key does not exist or its value is not an uint64.
+func (d *Dict) GetUint64(key string) (uint64, bool) {
+	if !d.lenientNumbers {
+		return getAs[uint64](d, key)
+	}
+
+	return toUint64(d.get(key))
+}
+
+// GetUint64Bytes is like GetUint64 with the key as bytes.
+func (d *Dict) GetUint64Bytes(key []byte) (uint64, bool) {
+	return d.GetUint64(strconv.B2S(key))
+}
+
+// GetFloat64 returns the float64 value of the key and true,
+// or false if the key does not exist or its value is not a float64.
+func (d *Dict) GetFloat64(key string) (float64, bool) {
+	if !d.lenientNumbers {
+		return getAs[float64](d, key)
+	}
+
+	return toFloat64(d.get(key))
+}
+
+// GetFloat64Bytes is like GetFloat64 with the key as bytes.
+func (d *Dict) GetFloat64Bytes(key []byte) (float64, bool) {
+	return d.GetFloat64(strconv.B2S(key))
+}
+
+// GetBool returns the bool value of the key and true,
+// or false if the key does not exist or its value is not a bool.
+func (d *Dict) GetBool(key string) (bool, bool) {
+	return getAs[bool](d, key)
+}
+
+// GetBoolBytes is like GetBool with the key as bytes.
+func (d *Dict) GetBoolBytes(key []byte) (bool, bool) {
+	return d.GetBool(strconv.B2S(key))
+}
+
+// GetByteSlice returns the []byte value of the key and true,
+// or false if the key does not exist or its value is not a []byte.
+func (d *Dict) GetByteSlice(key string) ([]byte, bool) {
+	return getAs[[]byte](d, key)
+}
+
+// GetByteSliceBytes is like GetByteSlice with the key as bytes.
+func (d *Dict) GetByteSliceBytes(key []byte) ([]byte, bool) {
+	return d.GetByteSlice(strconv.B2S(key))
+}
+
+// GetDict returns the nested *Dict value of the key and true,
+// or false if the key does not exist or its value is not a *Dict.
+func (d *Dict) GetDict(key string) (*Dict, bool) {
+	return getAs[*Dict](d, key)
+}
+
+// GetDictBytes is like GetDict with the key as bytes.
+func (d *Dict) GetDictBytes(key []byte) (*Dict, bool) {
+	return d.GetDict(strconv.B2S(key))
+}
+
+// GetTime returns the time.Time value of the key and true,
+// or false if the key does not exist or its value is not a time.Time.
+func (d *Dict) GetTime(key string) (time.Time, bool) {
+	return getAs[time.Time](d, key)
+}
+
+// GetTimeBytes is like GetTime with the key as bytes.
+func (d *Dict) GetTimeBytes(key []byte) (time.Time, bool) {
+	return d.GetTime(strconv.B2S(key))
+}
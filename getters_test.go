@@ -0,0 +1,162 @@
+package dictpool
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDict_GetString(t *testing.T) {
+	d := AcquireDict()
+	d.Set("str", "value")
+	d.Set("int", 1)
+
+	if v, ok := d.GetString("str"); !ok || v != "value" {
+		t.Errorf("Dict.GetString() = (%q, %v), want (%q, %v)", v, ok, "value", true)
+	}
+
+	if v, ok := d.GetStringBytes([]byte("str")); !ok || v != "value" {
+		t.Errorf("Dict.GetStringBytes() = (%q, %v), want (%q, %v)", v, ok, "value", true)
+	}
+
+	if _, ok := d.GetString("int"); ok {
+		t.Error("Dict.GetString() ok with a non string value")
+	}
+
+	if _, ok := d.GetString("missing"); ok {
+		t.Error("Dict.GetString() ok with a missing key")
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_GetNumbers(t *testing.T) {
+	d := AcquireDict()
+	d.Set("int", 1)
+	d.Set("int64", int64(2))
+	d.Set("uint64", uint64(3))
+	d.Set("float64", 4.0)
+
+	if v, ok := d.GetInt("int"); !ok || v != 1 {
+		t.Errorf("Dict.GetInt() = (%v, %v), want (%v, %v)", v, ok, 1, true)
+	}
+
+	if v, ok := d.GetInt64Bytes([]byte("int64")); !ok || v != 2 {
+		t.Errorf("Dict.GetInt64Bytes() = (%v, %v), want (%v, %v)", v, ok, 2, true)
+	}
+
+	if v, ok := d.GetUint64("uint64"); !ok || v != 3 {
+		t.Errorf("Dict.GetUint64() = (%v, %v), want (%v, %v)", v, ok, 3, true)
+	}
+
+	if v, ok := d.GetFloat64Bytes([]byte("float64")); !ok || v != 4 {
+		t.Errorf("Dict.GetFloat64Bytes() = (%v, %v), want (%v, %v)", v, ok, 4, true)
+	}
+
+	// Strict mode does not convert between numeric kinds.
+	if _, ok := d.GetInt("float64"); ok {
+		t.Error("Dict.GetInt() ok with a float64 value in strict mode")
+	}
+
+	if _, ok := d.GetFloat64("int"); ok {
+		t.Error("Dict.GetFloat64() ok with an int value in strict mode")
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_SetLenientNumbers(t *testing.T) {
+	d := AcquireDict()
+	d.SetLenientNumbers(true)
+
+	d.Set("float", 4.0)
+	d.Set("fraction", 4.5)
+	d.Set("negative", int8(-1))
+	d.Set("big", uint64(math.MaxUint64))
+	d.Set("str", "1")
+
+	if v, ok := d.GetInt("float"); !ok || v != 4 {
+		t.Errorf("Dict.GetInt() = (%v, %v), want (%v, %v)", v, ok, 4, true)
+	}
+
+	if v, ok := d.GetUint64Bytes([]byte("float")); !ok || v != 4 {
+		t.Errorf("Dict.GetUint64Bytes() = (%v, %v), want (%v, %v)", v, ok, 4, true)
+	}
+
+	if v, ok := d.GetIntBytes([]byte("negative")); !ok || v != -1 {
+		t.Errorf("Dict.GetIntBytes() = (%v, %v), want (%v, %v)", v, ok, -1, true)
+	}
+
+	if v, ok := d.GetFloat64("negative"); !ok || v != -1 {
+		t.Errorf("Dict.GetFloat64() = (%v, %v), want (%v, %v)", v, ok, -1, true)
+	}
+
+	if v, ok := d.GetFloat64("big"); !ok || v != math.MaxUint64 {
+		t.Errorf("Dict.GetFloat64() = (%v, %v), want (%v, %v)", v, ok, float64(math.MaxUint64), true)
+	}
+
+	if _, ok := d.GetInt64("fraction"); ok {
+		t.Error("Dict.GetInt64() ok with a fractional value")
+	}
+
+	if _, ok := d.GetUint64("negative"); ok {
+		t.Error("Dict.GetUint64() ok with a negative value")
+	}
+
+	if _, ok := d.GetInt64("big"); ok {
+		t.Error("Dict.GetInt64() ok with an overflowing value")
+	}
+
+	if _, ok := d.GetInt("str"); ok {
+		t.Error("Dict.GetInt() ok with a string value")
+	}
+
+	d.SetLenientNumbers(false)
+	ReleaseDict(d)
+}
+
+func TestDict_GetOthers(t *testing.T) {
+	now := time.Now()
+	sub := AcquireDict()
+
+	d := AcquireDict()
+	d.Set("bool", true)
+	d.Set("bytes", []byte("value"))
+	d.Set("dict", sub)
+	d.Set("time", now)
+
+	if v, ok := d.GetBool("bool"); !ok || !v {
+		t.Errorf("Dict.GetBool() = (%v, %v), want (%v, %v)", v, ok, true, true)
+	}
+
+	if v, ok := d.GetBoolBytes([]byte("bytes")); ok || v {
+		t.Errorf("Dict.GetBoolBytes() = (%v, %v), want (%v, %v)", v, ok, false, false)
+	}
+
+	if v, ok := d.GetByteSlice("bytes"); !ok || string(v) != "value" {
+		t.Errorf("Dict.GetByteSlice() = (%q, %v), want (%q, %v)", v, ok, "value", true)
+	}
+
+	if v, ok := d.GetByteSliceBytes([]byte("bytes")); !ok || string(v) != "value" {
+		t.Errorf("Dict.GetByteSliceBytes() = (%q, %v), want (%q, %v)", v, ok, "value", true)
+	}
+
+	if v, ok := d.GetDict("dict"); !ok || v != sub {
+		t.Errorf("Dict.GetDict() = (%p, %v), want (%p, %v)", v, ok, sub, true)
+	}
+
+	if v, ok := d.GetDictBytes([]byte("bool")); ok || v != nil {
+		t.Errorf("Dict.GetDictBytes() = (%p, %v), want (%v, %v)", v, ok, nil, false)
+	}
+
+	if v, ok := d.GetTime("time"); !ok || !v.Equal(now) {
+		t.Errorf("Dict.GetTime() = (%v, %v), want (%v, %v)", v, ok, now, true)
+	}
+
+	if _, ok := d.GetTimeBytes([]byte("missing")); ok {
+		t.Error("Dict.GetTimeBytes() ok with a missing key")
+	}
+
+	ReleaseDict(d)
+	ReleaseDict(sub)
+}
@@ -1,8 +1,13 @@
 module github.com/savsgio/dictpool
 
-go 1.13
+go 1.23
 
 require (
 	github.com/savsgio/gotils v0.0.0-20220530130905-52f3993e8d6d
 	github.com/tinylib/msgp v1.1.6
 )
+
+require (
+	github.com/philhofer/fwd v1.1.1 // indirect
+	github.com/valyala/bytebufferpool v1.0.0 // indirect
+)
@@ -0,0 +1,205 @@
+package dictpool
+
+import "hash/maphash"
+
+const minIndexSlots = 8
+
+// hashSeed randomizes the hashes of the keys per process,
+// so the keys, e.g. HTTP headers, could not be chosen to collide.
+var hashSeed = maphash.MakeSeed()
+
+// indexSlot stores the hash of a key and its position in Dict.D plus one,
+// so the zero value is an empty slot.
+type indexSlot struct {
+	hash uint32
+	pos  int
+}
+
+// hashIndex is an open-addressing (linear probing) table
+// of key hashes to positions in Dict.D.
+//
+// The slots are kept across resets, so a pooled dict reuses them.
+type hashIndex struct {
+	slots []indexSlot
+	count int
+}
+
+func hashKey(key string) uint32 {
+	return uint32(maphash.String(hashSeed, key))
+}
+
+func (ix *hashIndex) mask() uint32 {
+	return uint32(len(ix.slots) - 1)
+}
+
+func (ix *hashIndex) clear() {
+	if ix.count == 0 {
+		return
+	}
+
+	for i := range ix.slots {
+		ix.slots[i] = indexSlot{} // nolint:exhaustruct
+	}
+
+	ix.count = 0
+}
+
+// build indexes all the keys of the dict from scratch.
+func (ix *hashIndex) build(d *Dict) {
+	n := d.len()
+	size := minIndexSlots
+
+	for size < 2*(n+1) {
+		size <<= 1
+	}
+
+	if cap(ix.slots) >= size {
+		ix.slots = ix.slots[:size]
+
+		for i := range ix.slots {
+			ix.slots[i] = indexSlot{} // nolint:exhaustruct
+		}
+	} else {
+		ix.slots = make([]indexSlot, size)
+	}
+
+	ix.count = 0
+
+	for i := 0; i < n; i++ {
+		ix.put(d.keyMode.hash(d.D[i].Key), i)
+	}
+}
+
+func (ix *hashIndex) put(h uint32, pos int) {
+	mask := ix.mask()
+	i := h & mask
+
+	for ix.slots[i].pos != 0 {
+		i = (i + 1) & mask
+	}
+
+	ix.slots[i] = indexSlot{hash: h, pos: pos + 1}
+	ix.count++
+}
+
+// add indexes the key stored at the given position of the dict,
+// growing the table if needed.
+func (ix *hashIndex) add(d *Dict, pos int) {
+	if 2*(ix.count+1) > len(ix.slots) {
+		ix.build(d)
+
+		return
+	}
+
+	ix.put(d.keyMode.hash(d.D[pos].Key), pos)
+}
+
+// lookup returns the position of the key in the dict or -1.
+func (ix *hashIndex) lookup(d *Dict, key string) int {
+	if ix.count == 0 {
+		return -1
+	}
+
+	h := d.keyMode.hash(key)
+	mask := ix.mask()
+
+	for i := h & mask; ; i = (i + 1) & mask {
+		s := ix.slots[i]
+
+		if s.pos == 0 {
+			return -1
+		}
+
+		if s.hash == h && d.keyMode.equal(d.D[s.pos-1].Key, key) {
+			return s.pos - 1
+		}
+	}
+}
+
+// slotOf returns the slot that points to the given position.
+func (ix *hashIndex) slotOf(h uint32, pos int) uint32 {
+	mask := ix.mask()
+	i := h & mask
+
+	for ix.slots[i].pos != pos+1 {
+		i = (i + 1) & mask
+	}
+
+	return i
+}
+
+// remove deletes the slot that points to the given position,
+// shifting back the following slots of the cluster.
+func (ix *hashIndex) remove(h uint32, pos int) {
+	mask := ix.mask()
+	i := ix.slotOf(h, pos)
+
+	for {
+		j := i
+
+		for {
+			j = (j + 1) & mask
+			s := ix.slots[j]
+
+			if s.pos == 0 {
+				ix.slots[i] = indexSlot{} // nolint:exhaustruct
+				ix.count--
+
+				return
+			}
+
+			// The slot stays if its ideal position is cyclically in (i, j].
+			k := s.hash & mask
+			if (i <= j && i < k && k <= j) || (i > j && (i < k || k <= j)) {
+				continue
+			}
+
+			ix.slots[i] = s
+			i = j
+
+			break
+		}
+	}
+}
+
+// shift adds delta to every position greater than or equal to from.
+func (ix *hashIndex) shift(from, delta int) {
+	for i := range ix.slots {
+		if pos := ix.slots[i].pos; pos > from {
+			ix.slots[i].pos = pos + delta
+		}
+	}
+}
+
+// swap exchanges the positions i and j, whose key hashes are hi and hj.
+func (ix *hashIndex) swap(hi uint32, i int, hj uint32, j int) {
+	si := ix.slotOf(hi, i)
+	sj := ix.slotOf(hj, j)
+
+	ix.slots[si].pos, ix.slots[sj].pos = j+1, i+1
+}
+
+// setHashIndex enables or disables the hash index, building it if needed.
+func (d *Dict) setHashIndex(enabled bool) {
+	if enabled == d.hashed {
+		return
+	}
+
+	d.hashed = enabled
+
+	if enabled {
+		d.index.build(d)
+	} else {
+		d.index.clear()
+	}
+}
+
+// SetHashIndex enables or disables the hash index over D,
+// making Get and Has O(1) while keeping the order of D.
+// Del is still O(n), since the following keys are shifted.
+//
+// The index memory is kept when the dict is reset or released,
+// so D must not be modified directly while it's enabled.
+func (d *Dict) SetHashIndex(enabled bool) {
+	d.setHashIndex(enabled)
+}
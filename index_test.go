@@ -0,0 +1,154 @@
+package dictpool
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDict_SetHashIndex(t *testing.T) {
+	d := AcquireDict()
+
+	keys := genKeys(t, 100)
+	for i, k := range keys[:50] {
+		d.Set(k, i)
+	}
+
+	d.SetHashIndex(true)
+
+	for i, k := range keys[50:] {
+		d.Set(k, i+50)
+	}
+
+	for i, k := range keys {
+		if val := d.Get(k); val != i {
+			t.Errorf("Dict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	for i, kv := range d.D {
+		if kv.Key != keys[i] {
+			t.Fatalf("Dict.D[%d].Key == %q, want %q", i, kv.Key, keys[i])
+		}
+	}
+
+	if d.Has("other") {
+		t.Error("Dict.Has() = true for a missing key")
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+
+	d.SetHashIndex(false)
+	ReleaseDict(d)
+}
+
+func TestDict_HashIndexOperations(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+		d.SetHashIndex(true)
+
+		want := make(map[string]interface{})
+		rnd := rand.New(rand.NewSource(1)) // nolint:gosec
+
+		for i := 0; i < 5000; i++ {
+			key := strconv.Itoa(rnd.Intn(200))
+
+			switch op := rnd.Intn(10); {
+			case op < 6:
+				d.Set(key, i)
+				want[key] = i
+			case op < 9:
+				d.Del(key)
+				delete(want, key)
+			default:
+				if rnd.Intn(10) == 0 {
+					d.Reset()
+					want = make(map[string]interface{})
+				} else if d.Len() > 1 {
+					d.Swap(0, d.Len()-1)
+				}
+			}
+
+			if d.Len() != len(want) {
+				t.Fatalf("Dict.Len() == %d, want %d", d.Len(), len(want))
+			}
+
+			if err := d.Validate(); err != nil {
+				t.Fatalf("Dict.Validate() unexpected error: %v", err)
+			}
+		}
+
+		got := make(DictMap)
+		d.Map(got)
+
+		if !reflect.DeepEqual(got, DictMap(want)) {
+			t.Errorf("Dict.Map() == %v, want %v", got, want)
+		}
+
+		for k, v := range want {
+			if val := d.Get(k); val != v {
+				t.Errorf("Dict.Get() = '%v', want '%v'", val, v)
+			}
+		}
+
+		d.SetBinarySearch(false)
+		d.SetHashIndex(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_HashIndexReset(t *testing.T) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	for i, k := range genKeys(t, 100) {
+		d.Set(k, i)
+	}
+
+	slots := cap(d.index.slots)
+
+	d.Reset()
+
+	if cap(d.index.slots) != slots {
+		t.Errorf("Dict.Reset() index cap == %d, want %d", cap(d.index.slots), slots)
+	}
+
+	if d.index.count != 0 {
+		t.Errorf("Dict.Reset() index count == %d, want 0", d.index.count)
+	}
+
+	d.SetHashIndex(false)
+	ReleaseDict(d)
+}
+
+func Benchmark_GetHashIndex(b *testing.B) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	benchmarkGet(b, d, 10)
+}
+
+func Benchmark_GetHashIndexBigHeap(b *testing.B) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	benchmarkGet(b, d, 1000)
+}
+
+func Benchmark_SetHashIndexBigHeap(b *testing.B) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	benchmarkSet(b, d, 1000)
+}
+
+func Benchmark_DelHashIndexBigHeap(b *testing.B) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	benchmarkDel(b, d, 1000)
+}
@@ -0,0 +1,32 @@
+package dictpool
+
+import "iter"
+
+// All returns an iterator over the keys and values of the dict.
+//
+// It's safe to delete the current key during the iteration.
+func (d *Dict) All() iter.Seq2[string, any] {
+	return d.rangeKV
+}
+
+// KeysSeq returns an iterator over the keys of the dict.
+//
+// It's safe to delete the current key during the iteration.
+func (d *Dict) KeysSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		d.rangeKV(func(key string, _ any) bool {
+			return yield(key)
+		})
+	}
+}
+
+// ValuesSeq returns an iterator over the values of the dict.
+//
+// It's safe to delete the current key during the iteration.
+func (d *Dict) ValuesSeq() iter.Seq[any] {
+	return func(yield func(any) bool) {
+		d.rangeKV(func(_ string, value any) bool {
+			return yield(value)
+		})
+	}
+}
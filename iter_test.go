@@ -0,0 +1,122 @@
+package dictpool
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDict_All(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		want := map[string]any{"a": 1, "b": 2, "c": 3}
+		for k, v := range want {
+			d.Set(k, v)
+		}
+
+		if got := maps.Collect(d.All()); !reflect.DeepEqual(got, want) {
+			t.Errorf("Dict.All() == %v, want %v", got, want)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_AllBreak(t *testing.T) {
+	d := AcquireDict()
+	d.Set("a", 1)
+	d.Set("b", 2)
+	d.Set("c", 3)
+
+	var got []string
+
+	for k := range d.All() {
+		got = append(got, k)
+
+		if k == "b" {
+			break
+		}
+	}
+
+	if want := []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.All() keys == %v, want %v", got, want)
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_AllDel(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireDict()
+		d.SetBinarySearch(binary)
+
+		for _, k := range []string{"a", "b", "c", "d"} {
+			d.Set(k, true)
+		}
+
+		var got []string
+
+		for k := range d.All() {
+			got = append(got, k)
+
+			if k != "c" {
+				d.Del(k)
+			}
+		}
+
+		if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Dict.All() keys == %v, want %v", got, want)
+		}
+
+		if want := []string{"c"}; !reflect.DeepEqual(d.Keys(nil), want) {
+			t.Errorf("Dict.Keys() == %v, want %v", d.Keys(nil), want)
+		}
+
+		d.SetBinarySearch(false)
+		ReleaseDict(d)
+	}
+}
+
+func TestDict_KeysSeq(t *testing.T) {
+	d := AcquireDict()
+	d.Set("b", 1)
+	d.Set("a", 2)
+
+	if got, want := slices.Collect(d.KeysSeq()), []string{"b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.KeysSeq() == %v, want %v", got, want)
+	}
+
+	for k := range d.KeysSeq() {
+		d.Del(k)
+	}
+
+	if d.Len() != 0 {
+		t.Errorf("Dict.KeysSeq() deleting keys left %d keys", d.Len())
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_ValuesSeq(t *testing.T) {
+	d := AcquireDict()
+	d.Set("b", 1)
+	d.Set("a", 2)
+
+	if got, want := slices.Collect(d.ValuesSeq()), []any{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.ValuesSeq() == %v, want %v", got, want)
+	}
+
+	for v := range d.ValuesSeq() {
+		if v == 1 {
+			break
+		}
+
+		t.Errorf("Dict.ValuesSeq() not stopped after break")
+	}
+
+	ReleaseDict(d)
+}
@@ -0,0 +1,330 @@
+package dictpool
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+const hexDigits = "0123456789abcdef"
+
+// MarshalJSON implements json.Marshaler, writing the keys in the order of D.
+func (d *Dict) MarshalJSON() ([]byte, error) {
+	return d.appendJSON(nil)
+}
+
+// AppendJSON appends the JSON object of the dict to dst, with the keys in the order of D,
+// and returns the extended buffer.
+//
+// The values not supported by encoding/json are written as null,
+// use MarshalJSON to get the error.
+func (d *Dict) AppendJSON(dst []byte) []byte {
+	dst, _ = d.appendJSON(dst)
+
+	return dst
+}
+
+// appendJSON appends the JSON object of the dict to dst,
+// returning the first error of its values.
+func (d *Dict) appendJSON(dst []byte) ([]byte, error) {
+	if d == nil {
+		return append(dst, "null"...), nil
+	}
+
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	var err error
+
+	dst = append(dst, '{')
+
+	for i := range d.D {
+		kv := &d.D[i]
+
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		dst = appendJSONString(dst, kv.Key)
+		dst = append(dst, ':')
+
+		var valueErr error
+
+		if dst, valueErr = appendJSONValue(dst, kv.Value); err == nil {
+			err = valueErr
+		}
+	}
+
+	return append(dst, '}'), err
+}
+
+// appendJSONValue appends the JSON of the value to dst,
+// writing null if it's not supported by encoding/json.
+func appendJSONValue(dst []byte, v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return append(dst, "null"...), nil
+	case string:
+		return appendJSONString(dst, val), nil
+	case bool:
+		return strconv.AppendBool(dst, val), nil
+	case int:
+		return strconv.AppendInt(dst, int64(val), 10), nil
+	case int64:
+		return strconv.AppendInt(dst, val, 10), nil
+	case uint64:
+		return strconv.AppendUint(dst, val, 10), nil
+	case float64:
+		return appendJSONFloat(dst, val, 64)
+	case float32:
+		return appendJSONFloat(dst, float64(val), 32)
+	case []byte:
+		if val == nil {
+			return append(dst, "null"...), nil
+		}
+
+		dst = append(dst, '"')
+		dst = base64.StdEncoding.AppendEncode(dst, val)
+
+		return append(dst, '"'), nil
+	case *Dict:
+		return val.appendJSON(dst)
+	case []*Dict:
+		return appendJSONArray(dst, len(val), val == nil, func(dst []byte, i int) ([]byte, error) {
+			return val[i].appendJSON(dst)
+		})
+	case []interface{}:
+		return appendJSONArray(dst, len(val), val == nil, func(dst []byte, i int) ([]byte, error) {
+			return appendJSONValue(dst, val[i])
+		})
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return append(dst, "null"...), err
+	}
+
+	return append(dst, b...), nil
+}
+
+func appendJSONArray(
+	dst []byte, n int, isNil bool, appendElem func(dst []byte, i int) ([]byte, error),
+) ([]byte, error) {
+	if isNil {
+		return append(dst, "null"...), nil
+	}
+
+	var err error
+
+	dst = append(dst, '[')
+
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+
+		var elemErr error
+
+		if dst, elemErr = appendElem(dst, i); err == nil {
+			err = elemErr
+		}
+	}
+
+	return append(dst, ']'), err
+}
+
+// appendJSONFloat appends the float like encoding/json does.
+func appendJSONFloat(dst []byte, f float64, bitSize int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return append(dst, "null"...), &json.UnsupportedValueError{
+			Str: strconv.FormatFloat(f, 'g', -1, bitSize),
+		}
+	}
+
+	format := byte('f')
+
+	if abs := math.Abs(f); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+
+	dst = strconv.AppendFloat(dst, f, format, -1, bitSize)
+
+	// Clean up e-09 to e-9.
+	if n := len(dst); format == 'e' && n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+		dst[n-2] = dst[n-1]
+		dst = dst[:n-1]
+	}
+
+	return dst, nil
+}
+
+// appendJSONString appends the quoted and escaped string,
+// replacing the invalid UTF-8 with the replacement character.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+
+			i++
+			start = i
+
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+		default:
+			i += size
+
+			continue
+		}
+
+		i += size
+		start = i
+	}
+
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// The nested objects are decoded to nested dicts acquired from the pool,
+// and the keys are set honouring the lookup strategy of the dict.
+func (d *Dict) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case nil:
+		return nil
+	case json.Delim('{'):
+		return d.decodeJSONObject(dec)
+	}
+
+	return ErrNotObject
+}
+
+// decodeJSONObject decodes the members of an object whose opening brace
+// has already been read.
+func (d *Dict) decodeJSONObject(dec *json.Decoder) error {
+	d.reload()
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, _ := tok.(string)
+
+		value, owned, err := d.decodeJSONValue(dec)
+		if err != nil {
+			return err
+		}
+
+		d.set(key, value)
+
+		if owned {
+			d.D[d.indexOf(key)].owned = true
+		}
+	}
+
+	_, err := dec.Token()
+
+	return err
+}
+
+// decodeJSONValue decodes the next value, reporting whether the result
+// holds dicts owned by the dict.
+func (d *Dict) decodeJSONValue(dec *json.Decoder) (interface{}, bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		sub := d.newChild()
+
+		if err := sub.decodeJSONObject(dec); err != nil {
+			ReleaseDictDeep(sub)
+
+			return nil, false, err
+		}
+
+		return sub, true, nil
+	case json.Delim('['):
+		return d.decodeJSONArray(dec)
+	}
+
+	return tok, false, nil
+}
+
+// decodeJSONArray decodes the elements of an array whose opening bracket
+// has already been read.
+func (d *Dict) decodeJSONArray(dec *json.Decoder) (interface{}, bool, error) {
+	values := []interface{}{}
+	owned := false
+
+	for dec.More() {
+		value, valueOwned, err := d.decodeJSONValue(dec)
+		if err != nil {
+			releaseValue(values)
+
+			return nil, false, err
+		}
+
+		values = append(values, value)
+		owned = owned || valueOwned
+	}
+
+	if _, err := dec.Token(); err != nil {
+		releaseValue(values)
+
+		return nil, false, err
+	}
+
+	return values, owned, nil
+}
@@ -0,0 +1,248 @@
+package dictpool
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDict_MarshalJSON(t *testing.T) {
+	sub := AcquireDict()
+	sub.Set("z", 1)
+	sub.Set("a", "b")
+
+	d := AcquireDict()
+	d.Set("foo", "bar")
+	d.Set("sub", sub)
+	d.Set("list", []interface{}{1, nil, sub})
+	d.Set("dicts", []*Dict{sub})
+	d.Set("bytes", []byte("hi"))
+	d.Set("map", map[string]int{"k": 1})
+
+	want := `{"foo":"bar","sub":{"z":1,"a":"b"},"list":[1,null,{"z":1,"a":"b"}],` +
+		`"dicts":[{"z":1,"a":"b"}],"bytes":"aGk=","map":{"k":1}}`
+
+	got, err := d.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Errorf("Dict.MarshalJSON() = %s, want %s", got, want)
+	}
+
+	got, err = json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	if got := d.AppendJSON([]byte("x=")); string(got) != "x="+want {
+		t.Errorf("Dict.AppendJSON() = %s, want %s", got, "x="+want)
+	}
+
+	ReleaseDict(d)
+	ReleaseDict(sub)
+}
+
+func TestDict_MarshalJSONBinarySearch(t *testing.T) {
+	d := AcquireDict()
+	d.SetBinarySearch(true)
+	d.Set("b", 2)
+	d.Set("a", 1)
+
+	if got := string(d.AppendJSON(nil)); got != `{"a":1,"b":2}` {
+		t.Errorf("Dict.AppendJSON() = %s, want %s", got, `{"a":1,"b":2}`)
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_MarshalJSONValues(t *testing.T) {
+	values := []interface{}{
+		"", "foo", `quote " and \ backslash`, "\n\r\t\x00\x1f", "<html> & co", "ñandú 😀",
+		"\u2028\u2029", "invalid \xff utf8",
+		true, false, 0, -1, int64(math.MinInt64), uint64(math.MaxUint64),
+		0.0, 1.5, -2.25, 1e20, 1e21, 1e-6, 1e-7, 123456789.123, math.MaxFloat64, math.SmallestNonzeroFloat64,
+		float32(1.1), float32(1e-7), float32(3e21),
+		[]byte{}, []byte(nil), []int{1, 2}, struct{ A int }{A: 1},
+	}
+
+	for _, v := range values {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := appendJSONValue(nil, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// encoding/json escapes the HTML characters.
+		var compact []byte
+
+		if compact, err = json.Marshal(json.RawMessage(got)); err != nil {
+			t.Fatalf("appendJSONValue(%#v) = %s, invalid JSON: %v", v, got, err)
+		}
+
+		if string(compact) != string(want) {
+			t.Errorf("appendJSONValue(%#v) = %s, want %s", v, compact, want)
+		}
+	}
+}
+
+func TestDict_MarshalJSONUnsupported(t *testing.T) {
+	d := AcquireDict()
+	d.Set("nan", math.NaN())
+	d.Set("func", func() {})
+	d.Set("ok", 1)
+
+	if _, err := d.MarshalJSON(); err == nil {
+		t.Error("Dict.MarshalJSON() expected an error")
+	}
+
+	if got := string(d.AppendJSON(nil)); got != `{"nan":null,"func":null,"ok":1}` {
+		t.Errorf("Dict.AppendJSON() = %s, want %s", got, `{"nan":null,"func":null,"ok":1}`)
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_UnmarshalJSON(t *testing.T) {
+	data := `{"foo":"bar","num":1.5,"ok":true,"nil":null,` +
+		`"sub":{"z":1,"a":{"deep":"value"}},"list":[1,"two",{"three":3}],"empty":[]}`
+
+	d := AcquireDict()
+	d.Set("stale", "value")
+
+	if err := d.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Has("stale") {
+		t.Error("Dict.UnmarshalJSON() the dict has not been reseted")
+	}
+
+	keys := d.Keys(nil)
+	if want := []string{"foo", "num", "ok", "nil", "sub", "list", "empty"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Dict.UnmarshalJSON() keys = %v, want %v", keys, want)
+	}
+
+	sub, ok := d.GetDict("sub")
+	if !ok {
+		t.Fatalf("Dict.UnmarshalJSON() sub = %T, want *Dict", d.Get("sub"))
+	}
+
+	deep, _ := sub.GetDict("a")
+	if v := deep.Get("deep"); v != "value" {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, "value")
+	}
+
+	list, _ := d.Get("list").([]interface{})
+	if elem, ok := list[2].(*Dict); !ok || elem.Get("three") != 3.0 {
+		t.Errorf("Dict.UnmarshalJSON() list = %v", list)
+	}
+
+	if got := string(d.AppendJSON(nil)); got != data {
+		t.Errorf("Dict.AppendJSON() = %s, want %s", got, data)
+	}
+
+	ReleaseDictDeep(d)
+
+	if sub.Len() != 0 || deep.Len() != 0 {
+		t.Error("ReleaseDictDeep() decoded nested dicts not released")
+	}
+}
+
+func TestDict_UnmarshalJSONBinarySearch(t *testing.T) {
+	d := AcquireDict()
+	d.SetBinarySearch(true)
+	d.SetHashIndex(true)
+
+	if err := json.Unmarshal([]byte(`{"c":3,"a":1,"b":2,"a":4}`), d); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.UnmarshalJSON() invalid dict: %v", err)
+	}
+
+	if got := string(d.AppendJSON(nil)); got != `{"a":4,"b":2,"c":3}` {
+		t.Errorf("Dict.AppendJSON() = %s, want %s", got, `{"a":4,"b":2,"c":3}`)
+	}
+
+	d.SetHashIndex(false)
+	d.SetBinarySearch(false)
+	ReleaseDict(d)
+}
+
+func TestDict_UnmarshalJSONErrors(t *testing.T) {
+	d := AcquireDict()
+
+	if err := d.UnmarshalJSON([]byte("null")); err != nil {
+		t.Errorf("Dict.UnmarshalJSON(null) = %v", err)
+	}
+
+	if err := d.UnmarshalJSON([]byte("[1]")); !errors.Is(err, ErrNotObject) {
+		t.Errorf("Dict.UnmarshalJSON([1]) = %v, want %v", err, ErrNotObject)
+	}
+
+	for _, data := range []string{``, `{`, `{"a":`, `{"a":{"b":1}`, `{"a":[1,{"b":}]}`} {
+		if err := d.UnmarshalJSON([]byte(data)); err == nil {
+			t.Errorf("Dict.UnmarshalJSON(%s) expected an error", data)
+		}
+	}
+
+	ReleaseDict(d)
+}
+
+func Benchmark_DictMarshalJSON(b *testing.B) {
+	sub := AcquireDict()
+	sub.Set("id", 1234)
+	sub.Set("name", "John Doe")
+
+	d := AcquireDict()
+	d.Set("foo", "bar")
+	d.Set("num", 3.14)
+	d.Set("ok", true)
+	d.Set("user", sub)
+
+	var buf []byte
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf = d.AppendJSON(buf[:0])
+	}
+}
+
+func Benchmark_DictMapMarshalJSON(b *testing.B) {
+	sub := AcquireDict()
+	sub.Set("id", 1234)
+	sub.Set("name", "John Doe")
+
+	d := AcquireDict()
+	d.Set("foo", "bar")
+	d.Set("num", 3.14)
+	d.Set("ok", true)
+	d.Set("user", sub)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m := make(DictMap)
+		d.Map(m)
+
+		if _, err := json.Marshal(m); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package dictpool
+
+import (
+	"hash/maphash"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyMode is how the keys of a dict are compared.
+type KeyMode int
+
+const (
+	// KeyExact compares the keys byte by byte.
+	KeyExact KeyMode = iota
+
+	// KeyFoldASCII compares the keys ignoring the case of the ASCII letters,
+	// e.g. for HTTP headers.
+	KeyFoldASCII
+
+	// KeyFoldUnicode compares the keys under Unicode simple case folding,
+	// like strings.EqualFold.
+	KeyFoldUnicode
+)
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case folding.
+func foldRune(r rune) rune {
+	folded := r
+
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		folded = min(folded, f)
+	}
+
+	return folded
+}
+
+// equal reports whether the keys are equal.
+func (m KeyMode) equal(a, b string) bool {
+	switch m {
+	case KeyFoldASCII:
+		if len(a) != len(b) {
+			return false
+		}
+
+		for i := 0; i < len(a); i++ {
+			if lowerASCII(a[i]) != lowerASCII(b[i]) {
+				return false
+			}
+		}
+
+		return true
+	case KeyFoldUnicode:
+		return strings.EqualFold(a, b)
+	}
+
+	return a == b
+}
+
+// compare returns an integer comparing the keys, consistent with equal.
+func (m KeyMode) compare(a, b string) int {
+	switch m {
+	case KeyFoldASCII:
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if ca, cb := lowerASCII(a[i]), lowerASCII(b[i]); ca != cb {
+				return int(ca) - int(cb)
+			}
+		}
+
+		return len(a) - len(b)
+	case KeyFoldUnicode:
+		for a != "" && b != "" {
+			ra, na := utf8.DecodeRuneInString(a)
+			rb, nb := utf8.DecodeRuneInString(b)
+
+			if fa, fb := foldRune(ra), foldRune(rb); fa != fb {
+				return int(fa - fb)
+			}
+
+			a, b = a[na:], b[nb:]
+		}
+
+		return len(a) - len(b)
+	}
+
+	return strings.Compare(a, b)
+}
+
+// hash returns the seeded hash of the key, consistent with equal.
+func (m KeyMode) hash(key string) uint32 {
+	if m == KeyExact || (m == KeyFoldASCII && !hasUpperASCII(key)) {
+		return hashKey(key)
+	}
+
+	var (
+		h   maphash.Hash
+		buf [64]byte
+	)
+
+	h.SetSeed(hashSeed)
+
+	// The key is folded by chunks, the hash of the concatenation is the same.
+	b := buf[:0]
+
+	switch m {
+	case KeyFoldASCII:
+		for i := 0; i < len(key); i++ {
+			if len(b) == len(buf) {
+				h.Write(b) // nolint:errcheck
+				b = buf[:0]
+			}
+
+			b = append(b, lowerASCII(key[i]))
+		}
+	case KeyFoldUnicode:
+		for _, r := range key {
+			if len(b) > len(buf)-utf8.UTFMax {
+				h.Write(b) // nolint:errcheck
+				b = buf[:0]
+			}
+
+			b = utf8.AppendRune(b, foldRune(r))
+		}
+	}
+
+	h.Write(b) // nolint:errcheck
+
+	return uint32(h.Sum64())
+}
+
+func hasUpperASCII(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if 'A' <= key[i] && key[i] <= 'Z' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasPrefix reports whether the key begins with the prefix.
+func (m KeyMode) hasPrefix(key, prefix string) bool {
+	switch m {
+	case KeyFoldASCII:
+		return len(key) >= len(prefix) && m.equal(key[:len(prefix)], prefix)
+	case KeyFoldUnicode:
+		for prefix != "" {
+			if key == "" {
+				return false
+			}
+
+			rk, nk := utf8.DecodeRuneInString(key)
+			rp, np := utf8.DecodeRuneInString(prefix)
+
+			if foldRune(rk) != foldRune(rp) {
+				return false
+			}
+
+			key, prefix = key[nk:], prefix[np:]
+		}
+
+		return true
+	}
+
+	return strings.HasPrefix(key, prefix)
+}
+
+// fold returns the key in a form whose equality is the one of the mode.
+func (m KeyMode) fold(key string) string {
+	switch m {
+	case KeyFoldASCII:
+		b := []byte(key)
+
+		for i := range b {
+			b[i] = lowerASCII(b[i])
+		}
+
+		return string(b)
+	case KeyFoldUnicode:
+		return strings.Map(foldRune, key)
+	}
+
+	return key
+}
+
+// appendFold appends the key folded like fold to dst.
+func (m KeyMode) appendFold(dst []byte, key string) []byte {
+	switch m {
+	case KeyFoldASCII:
+		for i := 0; i < len(key); i++ {
+			dst = append(dst, lowerASCII(key[i]))
+		}
+
+		return dst
+	case KeyFoldUnicode:
+		for _, r := range key {
+			dst = utf8.AppendRune(dst, foldRune(r))
+		}
+
+		return dst
+	}
+
+	return append(dst, key...)
+}
+
+// SetKeyMode sets how the keys are compared by the lookups and the sorting,
+// preserving the spelling of the key first set.
+//
+// It should be set on an empty dict, since the existing keys
+// that become equal are not merged.
+func (d *Dict) SetKeyMode(mode KeyMode) {
+	d.keyMode = mode
+
+	if d.len() > 1 {
+		d.sorted = false
+	}
+
+	if d.hashed {
+		d.index.build(d)
+	}
+}
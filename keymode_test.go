@@ -0,0 +1,163 @@
+package dictpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyMode(t *testing.T) {
+	tests := []struct {
+		mode  KeyMode
+		a, b  string
+		equal bool
+	}{
+		{mode: KeyExact, a: "Content-Type", b: "Content-Type", equal: true},
+		{mode: KeyExact, a: "Content-Type", b: "content-type"},
+		{mode: KeyFoldASCII, a: "Content-Type", b: "content-TYPE", equal: true},
+		{mode: KeyFoldASCII, a: "Content-Type", b: "Content-Length"},
+		{mode: KeyFoldASCII, a: "ÑANDÚ", b: "ñandú"},
+		{mode: KeyFoldASCII, a: "abc", b: "abcd"},
+		{mode: KeyFoldUnicode, a: "ÑANDÚ", b: "ñandú", equal: true},
+		{mode: KeyFoldUnicode, a: "ΣΑΣ", b: "σας", equal: true},
+		{mode: KeyFoldUnicode, a: "k", b: "K", equal: true},
+		{mode: KeyFoldUnicode, a: "ñandú", b: "ñandu"},
+	}
+
+	for _, test := range tests {
+		if got := test.mode.equal(test.a, test.b); got != test.equal {
+			t.Errorf("KeyMode(%d).equal(%q, %q) = %v, want %v", test.mode, test.a, test.b, got, test.equal)
+		}
+
+		if got := test.mode.compare(test.a, test.b) == 0; got != test.equal {
+			t.Errorf("KeyMode(%d).compare(%q, %q) == 0 is %v, want %v", test.mode, test.a, test.b, got, test.equal)
+		}
+
+		if ab, ba := test.mode.compare(test.a, test.b), test.mode.compare(test.b, test.a); (ab < 0) != (ba > 0) {
+			t.Errorf("KeyMode(%d).compare(%q, %q) is not antisymmetric: %d and %d", test.mode, test.a, test.b, ab, ba)
+		}
+
+		if got := test.mode.fold(test.a) == test.mode.fold(test.b); got != test.equal {
+			t.Errorf("KeyMode(%d).fold(%q) == fold(%q) is %v, want %v", test.mode, test.a, test.b, got, test.equal)
+		}
+
+		if test.equal && test.mode.hash(test.a) != test.mode.hash(test.b) {
+			t.Errorf("KeyMode(%d).hash(%q) != hash(%q)", test.mode, test.a, test.b)
+		}
+	}
+}
+
+func TestKeyMode_Hash(t *testing.T) {
+	// The keys are longer than the chunks used to fold them.
+	keys := []string{"", "Content-Type", strings.Repeat("X-Forwarded-For-", 10), strings.Repeat("\u03a3\u00c5\u212a", 30)}
+
+	for _, mode := range []KeyMode{KeyExact, KeyFoldASCII, KeyFoldUnicode} {
+		for _, key := range keys {
+			if got, want := mode.hash(key), hashKey(mode.fold(key)); got != want {
+				t.Errorf("KeyMode(%d).hash(%q) = %d, want %d", mode, key, got, want)
+			}
+
+			allocs := testing.AllocsPerRun(10, func() {
+				mode.hash(key)
+			})
+
+			if allocs > 0 {
+				t.Errorf("KeyMode(%d).hash() allocs = %v, want 0", mode, allocs)
+			}
+		}
+	}
+}
+
+func TestDict_SetKeyMode(t *testing.T) {
+	modes := map[string]func(d *Dict){
+		"linear": func(d *Dict) {},
+		"binary": func(d *Dict) { d.SetBinarySearch(true) },
+		"hash":   func(d *Dict) { d.SetHashIndex(true) },
+	}
+
+	for name, setMode := range modes {
+		d := New()
+		d.SetKeyMode(KeyFoldASCII)
+		setMode(d)
+
+		d.Set("Content-Type", "text/plain")
+		d.Set("Accept", "*/*")
+		d.SetBytes([]byte("content-type"), "text/html")
+		d.Set("x-custom", 1)
+
+		if v := d.Get("CONTENT-TYPE"); v != "text/html" {
+			t.Errorf("%s: Dict.Get() = '%v', want '%v'", name, v, "text/html")
+		}
+
+		if !d.Has("X-Custom") || d.Len() != 3 {
+			t.Errorf("%s: Dict.Has() = %v, len = %d", name, d.Has("X-Custom"), d.Len())
+		}
+
+		// The spelling of the key first set is preserved.
+		keys := strings.Join(d.Keys(nil), ",")
+		if !strings.Contains(keys, "Content-Type") || strings.Contains(keys, "content-type") {
+			t.Errorf("%s: Dict.Keys() = %s", name, keys)
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("%s: Dict.Validate() unexpected error: %v", name, err)
+		}
+
+		d.Del("ACCEPT")
+
+		if d.Has("accept") || d.Len() != 2 {
+			t.Errorf("%s: Dict.Del() the key has not been deleted", name)
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("%s: Dict.Validate() unexpected error: %v", name, err)
+		}
+	}
+}
+
+func TestDict_SetKeyModeSorted(t *testing.T) {
+	d := New()
+	d.SetKeyMode(KeyFoldUnicode)
+	d.SetBinarySearch(true)
+
+	for _, k := range []string{"b", "Ñ", "A", "c", "ñ", "a"} {
+		d.Set(k, k)
+	}
+
+	if v := d.Get("ñ"); v != "ñ" {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, "ñ")
+	}
+
+	if keys := strings.Join(d.Keys(nil), ","); keys != "A,b,c,Ñ" {
+		t.Errorf("Dict.Keys() = %s, want %s", keys, "A,b,c,Ñ")
+	}
+
+	// The duplicated spellings are detected in binary search mode too.
+	d.D = append(d.D, KV{Key: "C"}) // nolint:exhaustruct
+
+	if err := d.Validate(); err == nil {
+		t.Error("Dict.Validate() expected an error")
+	}
+}
+
+func TestDict_ParseKeyMode(t *testing.T) {
+	d := New()
+	d.SetKeyMode(KeyFoldASCII)
+	d.Parse(DictMap{
+		"Key": "value",
+		"KEY": "value",
+		"sub": map[string]interface{}{"Sub-Key": 1},
+	})
+
+	if d.Len() != 2 {
+		t.Errorf("Dict.Parse() len = %d, want %d", d.Len(), 2)
+	}
+
+	sub, _ := d.GetDict("SUB")
+	if v := sub.Get("sub-key"); v != 1 {
+		t.Errorf("Dict.Get() = '%v', want '%v'", v, 1)
+	}
+
+	if err := d.Validate(); err != nil {
+		t.Errorf("Dict.Validate() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package dictpool
+
+// LookupMode is the strategy used by a dict to search its keys.
+type LookupMode int
+
+const (
+	// LookupLinear searches the keys sequentially, keeping the order of D.
+	LookupLinear LookupMode = iota
+
+	// LookupBinary keeps D sorted and searches the keys with binary search.
+	LookupBinary
+
+	// LookupHash searches the keys with a hash index, keeping the order of D.
+	LookupHash
+
+	// LookupAdaptive changes the strategy according to the length of the dict.
+	LookupAdaptive
+)
+
+// SetLookupMode sets the strategy used to search the keys,
+// replacing the ones set by SetBinarySearch, SetHashIndex and SetAdaptive.
+func (d *Dict) SetLookupMode(mode LookupMode) {
+	d.adaptive = false
+
+	switch mode {
+	case LookupBinary:
+		d.setHashIndex(false)
+		d.SetBinarySearch(true)
+	case LookupHash:
+		d.BinarySearch = false
+		d.setHashIndex(true)
+	case LookupAdaptive:
+		d.SetAdaptive(true)
+	default:
+		d.BinarySearch = false
+		d.setHashIndex(false)
+	}
+}
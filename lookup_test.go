@@ -0,0 +1,45 @@
+package dictpool
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestDict_SetLookupMode(t *testing.T) {
+	tests := []struct {
+		mode     LookupMode
+		binary   bool
+		hashed   bool
+		adaptive bool
+	}{
+		{mode: LookupLinear},
+		{mode: LookupBinary, binary: true},
+		{mode: LookupHash, hashed: true},
+		{mode: LookupAdaptive, adaptive: true},
+	}
+
+	for _, test := range tests {
+		d := New()
+		d.SetHashIndex(true)
+		d.SetAdaptive(true)
+
+		for i := 0; i < 10; i++ {
+			d.Set(strconv.Itoa(9-i), i)
+		}
+
+		d.SetLookupMode(test.mode)
+
+		if d.BinarySearch != test.binary || d.hashed != test.hashed || d.adaptive != test.adaptive {
+			t.Errorf("Dict.SetLookupMode(%d) = binary %v hashed %v adaptive %v, want %v %v %v",
+				test.mode, d.BinarySearch, d.hashed, d.adaptive, test.binary, test.hashed, test.adaptive)
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("Dict.SetLookupMode(%d) invalid dict: %v", test.mode, err)
+		}
+
+		if v := d.Get("3"); v != 6 {
+			t.Errorf("Dict.Get() = '%v', want '%v'", v, 6)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package dictpool
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+var defaultMultiPool = sync.Pool{
+	New: func() interface{} {
+		return NewMultiDict()
+	},
+}
+
+// MultiDict dictionary as slice whose keys could have multiple values,
+// like http.Header and url.Values.
+//
+// The values of a key are kept in insertion order.
+type MultiDict struct {
+	// D slice of KV for storage the data, with a KV per value.
+	D []KV
+
+	// Use binary search to the get an item.
+	// It's only useful on big heaps.
+	//
+	// The values of a key are kept grouped and in insertion order.
+	//
+	// Use SetBinarySearch to change it on a non empty multi dict.
+	BinarySearch bool
+
+	// sorted reports whether D is known to be sorted by key.
+	sorted bool
+
+	// keys stores the keys set from bytes.
+	keys keyArena
+}
+
+// AcquireMultiDict acquire new multi dict.
+func AcquireMultiDict() *MultiDict {
+	return defaultMultiPool.Get().(*MultiDict) // nolint:forcetypeassert
+}
+
+// ReleaseMultiDict release multi dict.
+func ReleaseMultiDict(d *MultiDict) {
+	d.Reset()
+	d.BinarySearch = false
+
+	d.keys.dropOversized()
+
+	if exceedsRetainedCapacity(cap(d.D)) {
+		d.D = nil
+		d.keys.buf = nil
+	}
+
+	defaultMultiPool.Put(d)
+}
+
+// NewMultiDict returns a new empty multi dict.
+func NewMultiDict() *MultiDict {
+	return new(MultiDict)
+}
+
+func (d *MultiDict) len() int {
+	return len(d.D)
+}
+
+// ensureSorted sorts the multi dict if it is not known to be sorted yet,
+// keeping the order of the values of each key.
+func (d *MultiDict) ensureSorted() {
+	if d.sorted {
+		return
+	}
+
+	sort.Stable((*multiKeySorter)(d))
+	d.sorted = true
+}
+
+// multiKeySorter sorts a multi dict by key.
+type multiKeySorter MultiDict
+
+func (s *multiKeySorter) Len() int {
+	return len(s.D)
+}
+
+func (s *multiKeySorter) Swap(i, j int) {
+	s.D[i], s.D[j] = s.D[j], s.D[i]
+}
+
+func (s *multiKeySorter) Less(i, j int) bool {
+	return s.D[i].Key < s.D[j].Key
+}
+
+// bounds returns the range of positions of the key in a sorted multi dict.
+func (d *MultiDict) bounds(key string) (int, int) {
+	d.ensureSorted()
+
+	n := d.len()
+	lo := sort.Search(n, func(i int) bool {
+		return key <= d.D[i].Key
+	})
+	hi := lo
+
+	for hi < n && d.D[hi].Key == key {
+		hi++
+	}
+
+	return lo, hi
+}
+
+func (d *MultiDict) indexOf(key string) int {
+	if d.BinarySearch {
+		if lo, hi := d.bounds(key); lo < hi {
+			return lo
+		}
+
+		return -1
+	}
+
+	for i := range d.D {
+		if d.D[i].Key == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (d *MultiDict) add(key string, value interface{}) {
+	n := d.len()
+	idx := n
+
+	if d.BinarySearch {
+		d.ensureSorted()
+
+		// Insert after the values of the key, so they keep the insertion order.
+		idx = sort.Search(n, func(i int) bool {
+			return key < d.D[i].Key
+		})
+	} else {
+		d.sorted = false
+	}
+
+	if cap(d.D) > n {
+		d.D = d.D[:n+1]
+	} else {
+		d.D = append(d.D, KV{}) // nolint:exhaustruct
+	}
+
+	copy(d.D[idx+1:], d.D[idx:])
+	d.D[idx] = KV{Key: key, Value: value} // nolint:exhaustruct
+}
+
+func (d *MultiDict) getAll(key string, dst []interface{}) []interface{} {
+	if d.BinarySearch {
+		lo, hi := d.bounds(key)
+
+		for i := lo; i < hi; i++ {
+			dst = append(dst, d.D[i].Value)
+		}
+
+		return dst
+	}
+
+	for i := range d.D {
+		if kv := &d.D[i]; kv.Key == key {
+			dst = append(dst, kv.Value)
+		}
+	}
+
+	return dst
+}
+
+// del deletes all the values of the key, keeping the order of the rest,
+// and reports whether there was any.
+func (d *MultiDict) del(key string) bool {
+	n := d.len()
+	lo, hi := 0, n
+
+	if d.BinarySearch {
+		lo, hi = d.bounds(key)
+		copy(d.D[lo:], d.D[hi:])
+		lo = n - (hi - lo)
+	} else {
+		for i := range d.D {
+			if d.D[i].Key != key {
+				d.D[lo] = d.D[i]
+				lo++
+			}
+		}
+	}
+
+	clear(d.D[lo:n])
+	d.D = d.D[:lo]
+
+	return lo < n
+}
+
+func (d *MultiDict) reset() {
+	clear(d.D)
+
+	d.D = d.D[:0]
+	d.sorted = true
+	d.keys.reset()
+}
+
+// Len is the number of values in the MultiDict.
+func (d *MultiDict) Len() int {
+	return d.len()
+}
+
+// SetBinarySearch enables or disables the binary search.
+//
+// When enabled, the multi dict is sorted lazily on the next operation that needs it,
+// keeping the order of the values of each key.
+func (d *MultiDict) SetBinarySearch(enabled bool) {
+	d.BinarySearch = enabled
+}
+
+// Add adds the value to the key, keeping its previous values.
+func (d *MultiDict) Add(key string, value interface{}) {
+	d.add(key, value)
+}
+
+// AddBytes adds the value to the key, keeping its previous values.
+//
+// The key is copied, so the buffer could be reused.
+func (d *MultiDict) AddBytes(key []byte, value interface{}) {
+	d.add(d.keys.intern(strconv.B2S(key), keyRefs(d.D, (*KV).keyRef)), value)
+}
+
+// Set replaces the values of the key with the given value.
+func (d *MultiDict) Set(key string, value interface{}) {
+	d.del(key)
+	d.add(key, value)
+}
+
+// SetBytes replaces the values of the key with the given value.
+//
+// The key is copied, so the buffer could be reused.
+func (d *MultiDict) SetBytes(key []byte, value interface{}) {
+	k := strconv.B2S(key)
+
+	d.del(k)
+	d.add(d.keys.intern(k, keyRefs(d.D, (*KV).keyRef)), value)
+}
+
+// Get returns the first value of the key, or nil if the key does not exist.
+func (d *MultiDict) Get(key string) interface{} {
+	if idx := d.indexOf(key); idx > -1 {
+		return d.D[idx].Value
+	}
+
+	return nil
+}
+
+// GetBytes returns the first value of the key, or nil if the key does not exist.
+func (d *MultiDict) GetBytes(key []byte) interface{} {
+	return d.Get(strconv.B2S(key))
+}
+
+// GetAll appends the values of the key to dst in insertion order
+// and returns the extended slice.
+func (d *MultiDict) GetAll(key string, dst []interface{}) []interface{} {
+	return d.getAll(key, dst)
+}
+
+// GetAllBytes appends the values of the key to dst in insertion order
+// and returns the extended slice.
+func (d *MultiDict) GetAllBytes(key []byte, dst []interface{}) []interface{} {
+	return d.GetAll(strconv.B2S(key), dst)
+}
+
+// Del deletes all the values of the key.
+func (d *MultiDict) Del(key string) {
+	d.del(key)
+}
+
+// DelBytes deletes all the values of the key.
+func (d *MultiDict) DelBytes(key []byte) {
+	d.Del(strconv.B2S(key))
+}
+
+// Has check if key exists.
+func (d *MultiDict) Has(key string) bool {
+	return d.indexOf(key) > -1
+}
+
+// HasBytes check if key exists.
+func (d *MultiDict) HasBytes(key []byte) bool {
+	return d.Has(strconv.B2S(key))
+}
+
+// Range calls fn sequentially for each key and value present in the multi dict,
+// once per value. If fn returns false, range stops the iteration.
+func (d *MultiDict) Range(fn func(key string, value interface{}) bool) {
+	if d.BinarySearch {
+		d.ensureSorted()
+	}
+
+	for i := range d.D {
+		if kv := &d.D[i]; !fn(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
+
+// Reset reset multi dict, reusing the memory of its keys as Dict.Reset.
+func (d *MultiDict) Reset() {
+	d.reset()
+}
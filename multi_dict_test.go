@@ -0,0 +1,217 @@
+package dictpool
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMultiDict(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireMultiDict()
+		d.BinarySearch = binary
+
+		d.Add("accept", "text/html")
+		d.Add("cookie", "a=1")
+		d.Add("accept", "text/plain")
+		d.AddBytes([]byte("cookie"), "b=2")
+		d.Add("accept", "*/*")
+
+		if d.Len() != 5 {
+			t.Errorf("MultiDict.Len() = %d, want %d", d.Len(), 5)
+		}
+
+		want := []interface{}{"text/html", "text/plain", "*/*"}
+		if got := d.GetAll("accept", nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+		}
+
+		if v := d.Get("cookie"); v != "a=1" {
+			t.Errorf("MultiDict.Get() = '%v', want '%v'", v, "a=1")
+		}
+
+		if v := d.Get("missing"); v != nil || d.Has("missing") {
+			t.Errorf("MultiDict.Get() = '%v', want '%v'", v, nil)
+		}
+
+		if got := d.GetAllBytes([]byte("missing"), nil); len(got) != 0 {
+			t.Errorf("MultiDict.GetAll() = %v, want empty", got)
+		}
+
+		d.Del("accept")
+
+		if d.Has("accept") || d.Len() != 2 {
+			t.Errorf("MultiDict.Del() the values have not been deleted: %v", d.D)
+		}
+
+		want = []interface{}{"a=1", "b=2"}
+		if got := d.GetAll("cookie", nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+		}
+
+		d.Set("cookie", "c=3")
+
+		want = []interface{}{"c=3"}
+		if got := d.GetAll("cookie", nil); !reflect.DeepEqual(got, want) {
+			t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+		}
+
+		ReleaseMultiDict(d)
+
+		if d.Len() != 0 || d.BinarySearch {
+			t.Error("ReleaseMultiDict() the multi dict has not been reseted")
+		}
+	}
+}
+
+func TestMultiDict_BinarySearchStable(t *testing.T) {
+	d := NewMultiDict()
+	d.BinarySearch = true
+
+	for i := 0; i < 100; i++ {
+		d.Add(strconv.Itoa(i%7), i)
+	}
+
+	if !sort.SliceIsSorted(d.D, func(i, j int) bool { return d.D[i].Key < d.D[j].Key }) {
+		t.Errorf("MultiDict.Add() unsorted keys: %v", d.D)
+	}
+
+	for k := 0; k < 7; k++ {
+		values := d.GetAll(strconv.Itoa(k), nil)
+
+		for i, v := range values {
+			if want := k + 7*i; v != want {
+				t.Errorf("MultiDict.GetAll(%d)[%d] = '%v', want '%v'", k, i, v, want)
+			}
+		}
+	}
+}
+
+func TestMultiDict_SetBinarySearch(t *testing.T) {
+	d := NewMultiDict()
+
+	for _, k := range []string{"b", "a", "b", "c", "a"} {
+		d.Add(k, len(d.D))
+	}
+
+	d.SetBinarySearch(true)
+
+	want := []interface{}{1, 4}
+	if got := d.GetAll("a", nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+	}
+
+	want = []interface{}{0, 2}
+	if got := d.GetAll("b", nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+	}
+}
+
+func TestMultiDict_BinarySearchField(t *testing.T) {
+	d := NewMultiDict()
+
+	for _, k := range []string{"c", "a", "b", "a"} {
+		d.Add(k, len(d.D))
+	}
+
+	// The filled multi dict is sorted by the next lookup.
+	d.BinarySearch = true
+
+	if !d.Has("a") || d.Get("c") != 0 {
+		t.Errorf("MultiDict.Has() = %v, MultiDict.Get() = '%v', want true and '%v'", d.Has("a"), d.Get("c"), 0)
+	}
+
+	want := []interface{}{1, 3}
+	if got := d.GetAll("a", nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiDict.GetAll() = %v, want %v", got, want)
+	}
+
+	d.Add("a", 4)
+
+	if !sort.SliceIsSorted(d.D, func(i, j int) bool { return d.D[i].Key < d.D[j].Key }) {
+		t.Errorf("MultiDict.Add() unsorted keys: %v", d.D)
+	}
+}
+
+func TestMultiDict_DelClear(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := NewMultiDict()
+		d.BinarySearch = binary
+
+		d.Add("a", 1)
+		d.Add("b", 2)
+		d.Add("a", 3)
+
+		d.DelBytes([]byte("a"))
+
+		for i, kv := range d.D[:cap(d.D)][d.Len():] {
+			if kv.Key != "" || kv.Value != nil {
+				t.Errorf("MultiDict.Del() stale reference at index %d: %v", d.Len()+i, kv)
+			}
+		}
+
+		if d.Len() != 1 || d.Get("b") != 2 {
+			t.Errorf("MultiDict.Del() = %v", d.D)
+		}
+	}
+}
+
+func TestMultiDict_AddBytesOwnedKey(t *testing.T) {
+	d := NewMultiDict()
+
+	buf := []byte("foo")
+	d.AddBytes(buf, 1)
+
+	copy(buf, "bar")
+	d.AddBytes(buf, 2)
+
+	if v := d.Get("foo"); v != 1 {
+		t.Errorf("MultiDict.Get() = '%v', want '%v'", v, 1)
+	}
+
+	if v := d.Get("bar"); v != 2 {
+		t.Errorf("MultiDict.Get() = '%v', want '%v'", v, 2)
+	}
+}
+
+func TestMultiDict_SetBytesDelBytes(t *testing.T) {
+	d := AcquireMultiDict()
+	key := []byte("0123456789abcdef")
+
+	for i := 0; i < 100000; i++ {
+		d.SetBytes(key, i)
+		d.DelBytes(key)
+	}
+
+	if cap(d.keys.buf) > minKeyArenaSize {
+		t.Errorf("MultiDict key arena capacity == %d, want <= %d", cap(d.keys.buf), minKeyArenaSize)
+	}
+
+	d.AddBytes([]byte(strings.Repeat("x", MaxRetainedKeysSize+1)), 1)
+	ReleaseMultiDict(d)
+
+	if d.keys.buf != nil {
+		t.Errorf("ReleaseMultiDict() retained key arena cap %d over the maximum %d", cap(d.keys.buf), MaxRetainedKeysSize)
+	}
+}
+
+func TestMultiDict_Range(t *testing.T) {
+	d := NewMultiDict()
+	d.Add("a", 1)
+	d.Add("a", 2)
+	d.Add("b", 3)
+
+	var got []interface{}
+
+	d.Range(func(key string, value interface{}) bool {
+		got = append(got, key, value)
+
+		return len(got) < 4
+	})
+
+	if want := []interface{}{"a", 1, "a", 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiDict.Range() = %v, want %v", got, want)
+	}
+}
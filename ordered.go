@@ -0,0 +1,149 @@
+package dictpool
+
+import (
+	"fmt"
+	"sort"
+)
+
+// sortedOrPanic sorts D if needed, panicking if the dict is neither
+// in binary search mode nor adaptive.
+//
+// An adaptive dict is sorted on demand also while it uses the linear search
+// or the hash index, which is rebuilt.
+func (d *Dict) sortedOrPanic(method string) {
+	if !d.BinarySearch && !d.adaptive {
+		panic(fmt.Errorf("dictpool: Dict.%s: %w", method, ErrNotSorted))
+	}
+
+	d.ensureSorted()
+}
+
+// searchUpper returns the position of the first key greater than the given one.
+func (d *Dict) searchUpper(key string) int {
+	return sort.Search(d.len(), func(i int) bool {
+		return d.compare(key, d.D[i].Key) < 0
+	})
+}
+
+// rangeFrom calls fn for each key/value in order from the position i
+// until fn returns false or the key reaches the end of the range.
+//
+// The current key could be deleted by fn without skipping the next one.
+func (d *Dict) rangeFrom(i int, end func(key string) bool, fn func(key string, value interface{}) bool) {
+	for i < d.len() {
+		key := d.D[i].Key
+
+		if end != nil && end(key) {
+			return
+		}
+
+		if !fn(key, d.D[i].Value) {
+			return
+		}
+
+		if i < d.len() && d.D[i].Key == key {
+			i++
+		}
+	}
+}
+
+// RangeFrom calls fn sequentially, in order, for each key greater than
+// or equal to the given one. If fn returns false, range stops the iteration.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) RangeFrom(key string, fn func(key string, value interface{}) bool) {
+	d.sortedOrPanic("RangeFrom")
+	d.rangeFrom(d.search(key), nil, fn)
+}
+
+// RangeBetween calls fn sequentially, in order, for each key greater than
+// or equal to lo and less than hi. If fn returns false, range stops the iteration.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) RangeBetween(lo, hi string, fn func(key string, value interface{}) bool) {
+	d.sortedOrPanic("RangeBetween")
+	d.rangeFrom(d.search(lo), func(key string) bool {
+		return d.compare(key, hi) >= 0
+	}, fn)
+}
+
+// RangePrefix calls fn sequentially, in order, for each key with the given prefix.
+// If fn returns false, range stops the iteration.
+//
+// The prefix is matched according to the key mode. With a custom comparator,
+// the keys with the prefix could be not contiguous, so all the keys are checked.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) RangePrefix(prefix string, fn func(key string, value interface{}) bool) {
+	d.sortedOrPanic("RangePrefix")
+
+	if d.cmp != nil {
+		d.rangeFrom(0, nil, func(key string, value interface{}) bool {
+			return !d.keyMode.hasPrefix(key, prefix) || fn(key, value)
+		})
+
+		return
+	}
+
+	d.rangeFrom(d.search(prefix), func(key string) bool {
+		return !d.keyMode.hasPrefix(key, prefix)
+	}, fn)
+}
+
+// Floor returns the greatest key less than or equal to the given one and its value,
+// or false if there is not any.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) Floor(key string) (string, interface{}, bool) {
+	d.sortedOrPanic("Floor")
+
+	return d.nth(d.searchUpper(key) - 1)
+}
+
+// Ceiling returns the least key greater than or equal to the given one and its value,
+// or false if there is not any.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) Ceiling(key string) (string, interface{}, bool) {
+	d.sortedOrPanic("Ceiling")
+
+	return d.nth(d.search(key))
+}
+
+// Min returns the least key and its value, or false if the dict is empty.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) Min() (string, interface{}, bool) {
+	d.sortedOrPanic("Min")
+
+	return d.nth(0)
+}
+
+// Max returns the greatest key and its value, or false if the dict is empty.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) Max() (string, interface{}, bool) {
+	d.sortedOrPanic("Max")
+
+	return d.nth(d.len() - 1)
+}
+
+// Nth returns the key in the position i of the order and its value,
+// or false if i is out of range.
+//
+// It panics with ErrNotSorted if neither the binary search nor the adaptive strategy is enabled.
+func (d *Dict) Nth(i int) (string, interface{}, bool) {
+	d.sortedOrPanic("Nth")
+
+	return d.nth(i)
+}
+
+func (d *Dict) nth(i int) (string, interface{}, bool) {
+	if i < 0 || i >= d.len() {
+		return "", nil, false
+	}
+
+	kv := &d.D[i]
+
+	return kv.Key, kv.Value, true
+}
@@ -0,0 +1,353 @@
+package dictpool
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func newSortedDict(keys ...string) *Dict {
+	d := New()
+	d.SetBinarySearch(true)
+
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	return d
+}
+
+func collectKeys(rangeFn func(fn func(key string, value interface{}) bool)) []string {
+	var keys []string
+
+	rangeFn(func(key string, _ interface{}) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	return keys
+}
+
+func TestDict_RangeFrom(t *testing.T) {
+	d := newSortedDict("d", "bb", "a", "c", "ba", "b")
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{key: "b", want: []string{"b", "ba", "bb", "c", "d"}},
+		{key: "bc", want: []string{"c", "d"}},
+		{key: "", want: []string{"a", "b", "ba", "bb", "c", "d"}},
+		{key: "e"},
+	}
+
+	for _, test := range tests {
+		got := collectKeys(func(fn func(key string, value interface{}) bool) {
+			d.RangeFrom(test.key, fn)
+		})
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Dict.RangeFrom(%q) = %v, want %v", test.key, got, test.want)
+		}
+	}
+
+	var got []string
+
+	d.RangeFrom("b", func(key string, _ interface{}) bool {
+		got = append(got, key)
+
+		return len(got) < 2
+	})
+
+	if want := []string{"b", "ba"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.RangeFrom() stopped at %v, want %v", got, want)
+	}
+}
+
+func TestDict_RangeFromDelete(t *testing.T) {
+	d := newSortedDict("a", "b", "c", "d")
+
+	got := collectKeys(func(fn func(key string, value interface{}) bool) {
+		d.RangeFrom("b", func(key string, value interface{}) bool {
+			d.Del(key)
+
+			return fn(key, value)
+		})
+	})
+
+	if want := []string{"b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.RangeFrom() = %v, want %v", got, want)
+	}
+
+	if keys := d.Keys(nil); !reflect.DeepEqual(keys, []string{"a"}) {
+		t.Errorf("Dict.Keys() = %v, want %v", keys, []string{"a"})
+	}
+}
+
+func TestDict_RangeBetween(t *testing.T) {
+	d := newSortedDict("d", "bb", "a", "c", "ba", "b")
+
+	tests := []struct {
+		lo, hi string
+		want   []string
+	}{
+		{lo: "b", hi: "c", want: []string{"b", "ba", "bb"}},
+		{lo: "", hi: "b", want: []string{"a"}},
+		{lo: "bc", hi: "z", want: []string{"c", "d"}},
+		{lo: "c", hi: "c"},
+		{lo: "d", hi: "a"},
+	}
+
+	for _, test := range tests {
+		got := collectKeys(func(fn func(key string, value interface{}) bool) {
+			d.RangeBetween(test.lo, test.hi, fn)
+		})
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Dict.RangeBetween(%q, %q) = %v, want %v", test.lo, test.hi, got, test.want)
+		}
+	}
+}
+
+func TestDict_RangePrefix(t *testing.T) {
+	d := newSortedDict("d", "bb", "a", "c", "ba", "b", "abc")
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{prefix: "b", want: []string{"b", "ba", "bb"}},
+		{prefix: "a", want: []string{"a", "abc"}},
+		{prefix: "ab", want: []string{"abc"}},
+		{prefix: "", want: []string{"a", "abc", "b", "ba", "bb", "c", "d"}},
+		{prefix: "x"},
+	}
+
+	for _, test := range tests {
+		got := collectKeys(func(fn func(key string, value interface{}) bool) {
+			d.RangePrefix(test.prefix, fn)
+		})
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Dict.RangePrefix(%q) = %v, want %v", test.prefix, got, test.want)
+		}
+	}
+}
+
+func TestDict_RangePrefixKeyMode(t *testing.T) {
+	d := New()
+	d.SetKeyMode(KeyFoldASCII)
+	d.SetBinarySearch(true)
+
+	for _, k := range []string{"X-Request-Id", "Accept", "x-forwarded-for", "X-Custom"} {
+		d.Set(k, k)
+	}
+
+	got := collectKeys(func(fn func(key string, value interface{}) bool) {
+		d.RangePrefix("x-", fn)
+	})
+
+	if want := []string{"X-Custom", "x-forwarded-for", "X-Request-Id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.RangePrefix() = %v, want %v", got, want)
+	}
+}
+
+func TestDict_RangeComparator(t *testing.T) {
+	d := New()
+	d.SetComparator(NaturalCompare)
+	d.SetBinarySearch(true)
+
+	for _, k := range []string{"item10", "item2", "item1", "other", "item20"} {
+		d.Set(k, k)
+	}
+
+	got := collectKeys(func(fn func(key string, value interface{}) bool) {
+		d.RangeBetween("item2", "item15", fn)
+	})
+
+	if want := []string{"item2", "item10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.RangeBetween() = %v, want %v", got, want)
+	}
+
+	// The keys with the prefix are not contiguous in natural order.
+	got = collectKeys(func(fn func(key string, value interface{}) bool) {
+		d.RangePrefix("item1", fn)
+	})
+
+	if want := []string{"item1", "item10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Dict.RangePrefix() = %v, want %v", got, want)
+	}
+
+	if key, _, _ := d.Floor("item15"); key != "item10" {
+		t.Errorf("Dict.Floor() = %q, want %q", key, "item10")
+	}
+}
+
+func TestDict_FloorCeiling(t *testing.T) {
+	d := newSortedDict("b", "d", "f")
+
+	tests := []struct {
+		key            string
+		floor, ceiling string
+	}{
+		{key: "a", ceiling: "b"},
+		{key: "b", floor: "b", ceiling: "b"},
+		{key: "c", floor: "b", ceiling: "d"},
+		{key: "f", floor: "f", ceiling: "f"},
+		{key: "g", floor: "f"},
+	}
+
+	for _, test := range tests {
+		key, value, ok := d.Floor(test.key)
+		if key != test.floor || ok != (test.floor != "") || (ok && d.Get(key) != value) {
+			t.Errorf("Dict.Floor(%q) = %q, %v, %v, want %q", test.key, key, value, ok, test.floor)
+		}
+
+		key, value, ok = d.Ceiling(test.key)
+		if key != test.ceiling || ok != (test.ceiling != "") || (ok && d.Get(key) != value) {
+			t.Errorf("Dict.Ceiling(%q) = %q, %v, %v, want %q", test.key, key, value, ok, test.ceiling)
+		}
+	}
+}
+
+func TestDict_MinMaxNth(t *testing.T) {
+	d := newSortedDict()
+
+	if _, _, ok := d.Min(); ok {
+		t.Error("Dict.Min() of an empty dict")
+	}
+
+	if _, _, ok := d.Max(); ok {
+		t.Error("Dict.Max() of an empty dict")
+	}
+
+	d = newSortedDict("c", "a", "b")
+
+	if key, value, _ := d.Min(); key != "a" || value != 1 {
+		t.Errorf("Dict.Min() = %q, %v, want %q, %v", key, value, "a", 1)
+	}
+
+	if key, value, _ := d.Max(); key != "c" || value != 0 {
+		t.Errorf("Dict.Max() = %q, %v, want %q, %v", key, value, "c", 0)
+	}
+
+	for i, want := range []string{"a", "b", "c"} {
+		if key, _, ok := d.Nth(i); !ok || key != want {
+			t.Errorf("Dict.Nth(%d) = %q, want %q", i, key, want)
+		}
+	}
+
+	for _, i := range []int{-1, 3} {
+		if _, _, ok := d.Nth(i); ok {
+			t.Errorf("Dict.Nth(%d) expected out of range", i)
+		}
+	}
+}
+
+func TestDict_OrderedNotSorted(t *testing.T) {
+	tests := map[string]func(d *Dict){
+		"RangeFrom":    func(d *Dict) { d.RangeFrom("a", nil) },
+		"RangeBetween": func(d *Dict) { d.RangeBetween("a", "b", nil) },
+		"RangePrefix":  func(d *Dict) { d.RangePrefix("a", nil) },
+		"Floor":        func(d *Dict) { d.Floor("a") },
+		"Ceiling":      func(d *Dict) { d.Ceiling("a") },
+		"Min":          func(d *Dict) { d.Min() },
+		"Max":          func(d *Dict) { d.Max() },
+		"Nth":          func(d *Dict) { d.Nth(0) },
+	}
+
+	for name, query := range tests {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrNotSorted) {
+					t.Errorf("Dict.%s() panic = '%v', want '%v'", name, err, ErrNotSorted)
+				}
+			}()
+
+			d := New()
+			d.Set("a", 1)
+
+			query(d)
+		}()
+	}
+}
+
+func TestDict_OrderedAdaptive(t *testing.T) {
+	// The dict uses the linear search, the binary search and the hash index.
+	for _, n := range []int{10, 20, 70, 500} {
+		d := New()
+		d.SetAdaptive(true)
+
+		for _, i := range rand.New(rand.NewSource(1)).Perm(n) { // nolint:gosec
+			d.Set(fmt.Sprintf("key%03d", i), i)
+		}
+
+		if key, _, _ := d.Min(); key != "key000" {
+			t.Errorf("%d keys: Dict.Min() = %q, want %q", n, key, "key000")
+		}
+
+		if key, _, _ := d.Max(); key != fmt.Sprintf("key%03d", n-1) {
+			t.Errorf("%d keys: Dict.Max() = %q, want %q", n, key, fmt.Sprintf("key%03d", n-1))
+		}
+
+		if key, _, _ := d.Floor("key0055"); n > 5 && key != "key005" {
+			t.Errorf("%d keys: Dict.Floor() = %q, want %q", n, key, "key005")
+		}
+
+		count := 0
+
+		d.RangePrefix("key00", func(key string, value interface{}) bool {
+			count++
+
+			return true
+		})
+
+		if count != 10 {
+			t.Errorf("%d keys: Dict.RangePrefix() visited %d keys, want %d", n, count, 10)
+		}
+
+		// The lookups still work after sorting.
+		d.Set("a", -1)
+
+		if key, _, _ := d.Min(); key != "a" {
+			t.Errorf("%d keys: Dict.Min() = %q, want %q", n, key, "a")
+		}
+
+		for i := 0; i < n; i++ {
+			if v := d.Get(fmt.Sprintf("key%03d", i)); v != i {
+				t.Errorf("%d keys: Dict.Get() = '%v', want '%v'", n, v, i)
+			}
+		}
+
+		if err := d.Validate(); err != nil {
+			t.Errorf("%d keys: Dict.Validate() unexpected error: %v", n, err)
+		}
+	}
+}
+
+func TestDict_OrderedAllocs(t *testing.T) {
+	d := newSortedDict("a", "b", "ba", "bb", "c")
+	n := 0
+
+	fn := func(_ string, _ interface{}) bool {
+		n++
+
+		return true
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		d.RangeFrom("b", fn)
+		d.RangeBetween("b", "c", fn)
+		d.RangePrefix("b", fn)
+		d.Floor("bc")
+		d.Ceiling("bc")
+	})
+
+	if allocs > 0 {
+		t.Errorf("ordered queries allocs = %v, want 0", allocs)
+	}
+}
@@ -0,0 +1,138 @@
+package dictpool
+
+import "reflect"
+
+// newChild returns a nested dict acquired from the pool
+// with the parsing options and the key mode of the dict.
+func (d *Dict) newChild() *Dict {
+	sub := AcquireDict()
+	sub.parseSlices = d.parseSlices
+	sub.keyMode = d.keyMode
+
+	return sub
+}
+
+func (d *Dict) parseMap(src map[string]interface{}) {
+	d.reload()
+
+	for k, v := range src {
+		value, owned := d.parseValue(v)
+		d.appendLoaded(k, value).owned = owned
+	}
+
+	d.adapt()
+}
+
+func (d *Dict) parseStrings(src map[string]string) {
+	d.reload()
+
+	for k, v := range src {
+		d.appendLoaded(k, v)
+	}
+
+	d.adapt()
+}
+
+func (d *Dict) parseReflect(src reflect.Value) {
+	d.reload()
+
+	iter := src.MapRange()
+	for iter.Next() {
+		value, owned := d.parseValue(iter.Value().Interface())
+		d.appendLoaded(iter.Key().String(), value).owned = owned
+	}
+
+	d.adapt()
+}
+
+// parseValue converts the maps with string keys of the value to nested dicts,
+// reporting whether the result holds dicts owned by the dict.
+func (d *Dict) parseValue(v interface{}) (interface{}, bool) {
+	switch val := v.(type) {
+	case nil, string, bool, int, int64, uint64, float64, []byte, *Dict:
+		return v, false
+	case DictMap:
+		sub := d.newChild()
+		sub.parseMap(val)
+
+		return sub, true
+	case map[string]interface{}:
+		sub := d.newChild()
+		sub.parseMap(val)
+
+		return sub, true
+	case map[string]string:
+		sub := d.newChild()
+		sub.parseStrings(val)
+
+		return sub, true
+	case []interface{}:
+		if d.parseSlices {
+			return d.parseSlice(val)
+		}
+
+		return v, false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch {
+	case isStringMap(rv.Type()):
+		sub := d.newChild()
+		sub.parseReflect(rv)
+
+		return sub, true
+	case d.parseSlices && rv.Kind() == reflect.Slice && isStringMap(rv.Type().Elem()):
+		dicts := make([]*Dict, rv.Len())
+
+		for i := range dicts {
+			dicts[i] = d.newChild()
+			dicts[i].parseReflect(rv.Index(i))
+		}
+
+		return dicts, true
+	}
+
+	return v, false
+}
+
+// parseSlice converts a slice whose elements are all maps to []*Dict,
+// otherwise it returns a copy of the slice with the maps converted to dicts.
+//
+// The copy is not owned if it contains dicts not created by the dict.
+func (d *Dict) parseSlice(src []interface{}) (interface{}, bool) {
+	allMaps, owned := len(src) > 0, true
+	values := make([]interface{}, len(src))
+
+	for i := range src {
+		values[i], _ = d.parseValue(src[i])
+
+		_, parsed := values[i].(*Dict)
+		_, wasDict := src[i].(*Dict)
+
+		allMaps = allMaps && parsed && !wasDict
+		owned = owned && !wasDict
+	}
+
+	if !allMaps {
+		return values, owned
+	}
+
+	dicts := make([]*Dict, len(values))
+
+	for i := range values {
+		dicts[i] = values[i].(*Dict) // nolint:forcetypeassert
+	}
+
+	return dicts, true
+}
+
+func isStringMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// SetParseSlices enables or disables the conversion of the slices
+// whose elements are maps into []*Dict when parsing.
+func (d *Dict) SetParseSlices(enabled bool) {
+	d.parseSlices = enabled
+}
@@ -0,0 +1,118 @@
+package dictpool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDict_ParseRoundTrip(t *testing.T) {
+	sub := AcquireDict()
+	sub.Set("subK", "subV")
+
+	d1 := AcquireDict()
+	d1.Set("key", "value")
+	d1.Set("sub", sub)
+
+	m1 := make(DictMap)
+	d1.Map(m1)
+
+	d2 := AcquireDict()
+	d2.Parse(m1)
+
+	m2 := make(DictMap)
+	d2.Map(m2)
+
+	if !reflect.DeepEqual(m1, m2) {
+		t.Errorf("Dict.Parse(Dict.Map()) == %v, want %v", m2, m1)
+	}
+
+	if _, ok := d2.GetDict("sub"); !ok {
+		t.Errorf("Dict.Parse() nested DictMap not converted: %T", d2.Get("sub"))
+	}
+
+	ReleaseDict(d1)
+	ReleaseDict(d2)
+	ReleaseDict(sub)
+}
+
+func TestDict_ParseTypedMaps(t *testing.T) {
+	type name string
+
+	m := DictMap{
+		"strings": map[string]string{"a": "b"},
+		"any":     map[string]any{"c": 1},
+		"ints":    map[string]int{"d": 2},
+		"named":   map[name]map[string]bool{"e": {"f": true}},
+		"other":   map[int]string{1: "g"},
+	}
+
+	d := AcquireDict()
+	d.Parse(m)
+
+	if sub, ok := d.GetDict("strings"); !ok || sub.Get("a") != "b" {
+		t.Errorf("Dict.Parse() map[string]string not converted: %v", d.Get("strings"))
+	}
+
+	if sub, ok := d.GetDict("any"); !ok || sub.Get("c") != 1 {
+		t.Errorf("Dict.Parse() map[string]any not converted: %v", d.Get("any"))
+	}
+
+	if sub, ok := d.GetDict("ints"); !ok || sub.Get("d") != 2 {
+		t.Errorf("Dict.Parse() map[string]int not converted: %v", d.Get("ints"))
+	}
+
+	named, ok := d.GetDict("named")
+	if !ok {
+		t.Fatalf("Dict.Parse() map[name]map[string]bool not converted: %v", d.Get("named"))
+	}
+
+	if sub, ok := named.GetDict("e"); !ok || sub.Get("f") != true {
+		t.Errorf("Dict.Parse() nested map[string]bool not converted: %v", named.Get("e"))
+	}
+
+	if _, ok := d.Get("other").(map[int]string); !ok {
+		t.Errorf("Dict.Parse() map[int]string converted: %v", d.Get("other"))
+	}
+
+	ReleaseDict(d)
+}
+
+func TestDict_SetParseSlices(t *testing.T) {
+	m := DictMap{
+		"maps":  []interface{}{map[string]interface{}{"a": 1}, DictMap{"b": 2}},
+		"mixed": []interface{}{map[string]interface{}{"c": 3}, []byte("d")},
+		"typed": []map[string]int{{"e": 4}},
+	}
+
+	d := AcquireDict()
+	d.Parse(m)
+
+	if _, ok := d.Get("maps").([]interface{}); !ok {
+		t.Errorf("Dict.Parse() slice converted without SetParseSlices: %T", d.Get("maps"))
+	}
+
+	d.SetParseSlices(true)
+	d.Parse(m)
+
+	maps, ok := d.Get("maps").([]*Dict)
+	if !ok || len(maps) != 2 || maps[0].Get("a") != 1 || maps[1].Get("b") != 2 {
+		t.Errorf("Dict.Parse() slice of maps not converted: %v", d.Get("maps"))
+	}
+
+	mixed, ok := d.Get("mixed").([]interface{})
+	if !ok || len(mixed) != 2 {
+		t.Fatalf("Dict.Parse() mixed slice == %v", d.Get("mixed"))
+	}
+
+	if sub, ok := mixed[0].(*Dict); !ok || sub.Get("c") != 3 {
+		t.Errorf("Dict.Parse() map in mixed slice not converted: %v", mixed[0])
+	}
+
+	typed, ok := d.Get("typed").([]*Dict)
+	if !ok || len(typed) != 1 || typed[0].Get("e") != 4 {
+		t.Errorf("Dict.Parse() []map[string]int not converted: %v", d.Get("typed"))
+	}
+
+	d.SetParseSlices(false)
+	ReleaseDict(d)
+}
@@ -2,19 +2,198 @@ package dictpool
 
 import "sync"
 
-var defaultPool = sync.Pool{
-	New: func() interface{} {
-		return New()
-	},
+// MaxRetainedCapacity is the maximum capacity of D retained by a released dict.
+// The bigger backing arrays are dropped, so they could be garbage collected.
+// A negative value means no limit.
+//
+// It must be set before using the pools.
+var MaxRetainedCapacity = 4096
+
+// MaxRetainedKeysSize is the maximum size in bytes of the key arena retained
+// by a released dict, i.e. the copies of the keys set from byte slices.
+// A negative value means no limit.
+//
+// It must be set before using the pools.
+var MaxRetainedKeysSize = 64 << 10
+
+var defaultPool Pool
+
+// Pool of dicts sharing the same options.
+//
+// The zero value is ready to use, with the options of a new dict.
+// The options must not be changed once the pool is in use.
+type Pool struct {
+	// InitialCapacity is the capacity of D preallocated for the new dicts.
+	InitialCapacity int
+
+	// MaxCapacity is the maximum capacity of D retained by a released dict.
+	// A zero value means MaxRetainedCapacity and a negative one means no limit.
+	MaxCapacity int
+
+	// LookupMode is the lookup strategy of the acquired dicts.
+	LookupMode LookupMode
+
+	// OnRelease is called, if not nil, with the released dict before resetting it.
+	OnRelease func(d *Dict)
+
+	// Calibrate enables the calibration of the pool with the capacity of D
+	// of the released dicts. The new dicts are preallocated with the 95th percentile
+	// of it, if bigger than InitialCapacity, and the released dicts far above it
+	// are discarded.
+	Calibrate bool
+
+	pool        sync.Pool
+	counters    poolCounters
+	calibration poolCalibration
 }
 
 // AcquireDict acquire new dict.
 func AcquireDict() *Dict {
-	return defaultPool.Get().(*Dict) // nolint:forcetypeassert
+	return defaultPool.Acquire()
 }
 
 // ReleaseDict release dict.
 func ReleaseDict(d *Dict) {
+	defaultPool.Release(d)
+}
+
+// Acquire acquire new dict from the pool.
+func (p *Pool) Acquire() *Dict {
+	p.counters.acquires.Add(1)
+
+	if v := p.pool.Get(); v != nil {
+		d := v.(*Dict) // nolint:forcetypeassert
+		d.debug.acquire()
+
+		return d
+	}
+
+	p.counters.news.Add(1)
+
+	d := New()
+
+	if n := p.initialCapacity(); n > 0 {
+		d.D = make([]KV, 0, n)
+	}
+
+	d.SetLookupMode(p.LookupMode)
+	d.debug.acquire()
+
+	return d
+}
+
+// Release release dict to the pool, restoring the options of the pool.
+func (p *Pool) Release(d *Dict) {
+	d.debug.checkRelease()
+
+	if p.OnRelease != nil {
+		p.OnRelease(d)
+	}
+
+	p.counters.releases.Add(1)
+	p.counters.lens.add(d.len())
+	p.counters.caps.add(cap(d.D))
+
+	if p.discard(cap(d.D)) {
+		p.counters.discarded.Add(1)
+		d.debug.release()
+
+		return
+	}
+
 	d.Reset()
-	defaultPool.Put(d)
+	d.resetOptions()
+
+	if d.dropOversized(p.maxCapacity()) {
+		p.counters.dropped.Add(1)
+	}
+
+	d.SetLookupMode(p.LookupMode)
+	d.debug.release()
+
+	p.pool.Put(d)
+}
+
+// ReleaseDeep release dict to the pool like ReleaseDictDeep.
+func (p *Pool) ReleaseDeep(d *Dict) {
+	d.releaseOwned()
+	p.Release(d)
+}
+
+func (p *Pool) maxCapacity() int {
+	if p.MaxCapacity == 0 {
+		return MaxRetainedCapacity
+	}
+
+	return p.MaxCapacity
+}
+
+func exceedsCapacity(n, maxCapacity int) bool {
+	return maxCapacity >= 0 && n > maxCapacity
+}
+
+func exceedsRetainedCapacity(n int) bool {
+	return exceedsCapacity(n, MaxRetainedCapacity)
+}
+
+// resetOptions restores the options of a new dict, keeping the memory of a reset dict.
+func (d *Dict) resetOptions() {
+	kvs, slots, keys, debug := d.D, d.index.slots, d.keys, d.debug
+
+	*d = Dict{} // nolint:exhaustruct
+	d.D, d.index.slots, d.keys, d.debug = kvs, slots, keys, debug
+	d.sorted = true
+}
+
+// dropOversized drops the memory of a reset dict exceeding the given capacity
+// and reports whether it was dropped.
+//
+// The key arena is dropped independently when it exceeds MaxRetainedKeysSize.
+func (d *Dict) dropOversized(maxCapacity int) bool {
+	d.keys.dropOversized()
+
+	if !exceedsCapacity(cap(d.D), maxCapacity) {
+		return false
+	}
+
+	d.D = nil
+	d.index.slots = nil
+	d.keys.buf = nil
+
+	return true
+}
+
+// ReleaseDictDeep release dict and the nested dicts owned by it,
+// i.e. the ones created by Parse, clearing the references of its values.
+//
+// The nested dicts set by the caller are not released.
+func ReleaseDictDeep(d *Dict) {
+	d.releaseOwned()
+	ReleaseDict(d)
+}
+
+// releaseOwned releases the nested dicts owned by the dict.
+func (d *Dict) releaseOwned() {
+	for i := range d.D {
+		if kv := &d.D[i]; kv.owned {
+			releaseValue(kv.Value)
+		}
+	}
+}
+
+func releaseValue(v interface{}) {
+	switch val := v.(type) {
+	case *Dict:
+		ReleaseDictDeep(val)
+	case []*Dict:
+		for _, sd := range val {
+			ReleaseDictDeep(sd)
+		}
+	case []interface{}:
+		for _, e := range val {
+			if sd, ok := e.(*Dict); ok {
+				ReleaseDictDeep(sd)
+			}
+		}
+	}
 }
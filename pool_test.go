@@ -1,6 +1,12 @@
 package dictpool
 
-import "testing"
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/tinylib/msgp/msgp"
+)
 
 func TestAcquireDict(t *testing.T) {
 	d := AcquireDict()
@@ -20,3 +26,271 @@ func TestReleaseDict(t *testing.T) {
 		t.Error("the dict has not been reseted")
 	}
 }
+
+func TestReleaseDictDeep(t *testing.T) {
+	user := AcquireDict()
+	user.Set("key", "value")
+
+	d := AcquireDict()
+	d.SetParseSlices(true)
+	d.Parse(DictMap{
+		"sub":   map[string]interface{}{"subsub": map[string]interface{}{"key": "value"}},
+		"slice": []interface{}{map[string]interface{}{"key": "value"}},
+		"mixed": []interface{}{map[string]interface{}{"key": "value"}, user},
+	})
+	d.Set("user", user)
+
+	sub, _ := d.GetDict("sub")
+	subsub, _ := sub.GetDict("subsub")
+	slice, _ := d.Get("slice").([]*Dict)
+	mixed, _ := d.Get("mixed").([]interface{})
+	parsed, _ := mixed[0].(*Dict)
+
+	ReleaseDictDeep(d)
+
+	for _, owned := range []*Dict{sub, subsub, slice[0]} {
+		if owned.Len() != 0 {
+			t.Errorf("ReleaseDictDeep() owned nested dict not released: %v", owned.D)
+		}
+	}
+
+	// The mixed slice contains a dict set by the caller, so it's not owned.
+	if parsed.Len() == 0 {
+		t.Error("ReleaseDictDeep() released a nested dict of a not owned slice")
+	}
+
+	if user.Len() == 0 {
+		t.Error("ReleaseDictDeep() released a nested dict set by the caller")
+	}
+
+	for i, kv := range d.D[:cap(d.D)] {
+		if kv.Key != "" || kv.Value != nil {
+			t.Errorf("ReleaseDictDeep() stale reference at index %d: %v", i, kv)
+		}
+	}
+
+	d.SetParseSlices(false)
+	ReleaseDict(user)
+}
+
+func TestReleaseDictDeepOverwritten(t *testing.T) {
+	d := AcquireDict()
+	d.Parse(DictMap{"sub": map[string]interface{}{"key": "value"}})
+
+	sub, _ := d.GetDict("sub")
+
+	// The value is no longer owned once it's overwritten by the caller.
+	d.Set("sub", sub)
+
+	ReleaseDictDeep(d)
+
+	if sub.Len() == 0 {
+		t.Error("ReleaseDictDeep() released an overwritten nested dict")
+	}
+
+	ReleaseDict(sub)
+}
+
+func TestDict_ReleaseReplacedOwned(t *testing.T) {
+	tests := map[string]func(d *Dict){
+		"Parse": func(d *Dict) { d.Parse(DictMap{"other": 1}) },
+		"Set":   func(d *Dict) { d.Set("sub", 1) },
+		"Del":   func(d *Dict) { d.Del("sub") },
+		"UnmarshalJSON": func(d *Dict) {
+			if err := d.UnmarshalJSON([]byte(`{"other": 1}`)); err != nil {
+				t.Fatal(err)
+			}
+		},
+		"UnmarshalMsg": func(d *Dict) {
+			if _, err := d.UnmarshalMsg(msgp.AppendMapHeader(nil, 0)); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}
+
+	for name, replace := range tests {
+		d := AcquireDict()
+		d.Parse(DictMap{"sub": map[string]interface{}{"key": "value"}})
+
+		sub, _ := d.GetDict("sub")
+
+		// The owned nested dicts are returned to the pool instead of dropped.
+		replace(d)
+
+		if sub.Len() != 0 {
+			t.Errorf("%s: owned nested dict not released: %v", name, sub.D)
+		}
+
+		ReleaseDictDeep(d)
+	}
+}
+
+func TestDict_UnmarshalJSONDuplicateOwned(t *testing.T) {
+	d := AcquireDict()
+
+	if err := d.UnmarshalJSON([]byte(`{"sub": {"key": "value"}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	sub, _ := d.GetDict("sub")
+
+	if err := d.UnmarshalJSON([]byte(`{"sub": {"key": 1}, "sub": {"key": 2}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sub.Len() != 0 {
+		t.Errorf("Dict.UnmarshalJSON() owned nested dict not released: %v", sub.D)
+	}
+
+	if last, _ := d.GetDict("sub"); last == nil || last.Get("key") != float64(2) {
+		t.Errorf("Dict.UnmarshalJSON() = %v, want the last duplicate", d.D)
+	}
+
+	ReleaseDictDeep(d)
+}
+
+func TestDict_SwapValueOwned(t *testing.T) {
+	d := AcquireDict()
+	d.Parse(DictMap{"sub": map[string]interface{}{"key": "value"}})
+
+	// The previous value is returned to the caller, so it's not released.
+	previous, _ := d.SwapValue("sub", 1)
+
+	if sub, _ := previous.(*Dict); sub == nil || sub.Len() != 1 {
+		t.Errorf("Dict.SwapValue() released the previous value: %v", previous)
+	}
+
+	ReleaseDictDeep(d)
+}
+
+func TestReleaseDictOversizedKeys(t *testing.T) {
+	d := AcquireDict()
+	d.SetBytes(bytes.Repeat([]byte("x"), MaxRetainedKeysSize+1), 1)
+
+	ReleaseDict(d)
+
+	if d.keys.buf != nil || d.D == nil {
+		t.Errorf("ReleaseDict() retained key arena cap %d over the maximum %d", cap(d.keys.buf), MaxRetainedKeysSize)
+	}
+}
+
+func TestReleaseDictOversized(t *testing.T) {
+	d := AcquireDict()
+	d.SetHashIndex(true)
+
+	for i := 0; i <= MaxRetainedCapacity; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+
+	d.SetHashIndex(false)
+	ReleaseDict(d)
+
+	if d.D != nil || d.index.slots != nil {
+		t.Errorf("ReleaseDict() retained cap %d over the maximum %d", cap(d.D), MaxRetainedCapacity)
+	}
+
+	d = AcquireDict()
+	d.Set("key", "value")
+
+	ReleaseDict(d)
+
+	if cap(d.D) == 0 {
+		t.Error("ReleaseDict() dropped a backing array under the maximum")
+	}
+}
+
+func TestPool_Acquire(t *testing.T) {
+	p := &Pool{InitialCapacity: 32, LookupMode: LookupHash}
+
+	d := p.Acquire()
+
+	if cap(d.D) != p.InitialCapacity {
+		t.Errorf("Pool.Acquire() cap = %d, want %d", cap(d.D), p.InitialCapacity)
+	}
+
+	if !d.hashed {
+		t.Error("Pool.Acquire() the lookup mode has not been applied")
+	}
+
+	p.Release(d)
+}
+
+func TestPool_Release(t *testing.T) {
+	var released *Dict
+
+	p := &Pool{
+		LookupMode: LookupBinary,
+		OnRelease: func(d *Dict) {
+			if d.Len() != 1 {
+				t.Errorf("Pool.OnRelease() len = %d, want %d", d.Len(), 1)
+			}
+
+			released = d
+		},
+	}
+
+	d := p.Acquire()
+	d.Set("key", "value")
+	d.SetHashIndex(true)
+	d.SetLenientNumbers(true)
+	d.SetParseSlices(true)
+
+	p.Release(d)
+
+	if released != d {
+		t.Error("Pool.Release() OnRelease has not been called")
+	}
+
+	if d.Len() != 0 {
+		t.Error("Pool.Release() the dict has not been reseted")
+	}
+
+	if !d.BinarySearch || d.hashed || d.lenientNumbers || d.parseSlices {
+		t.Errorf("Pool.Release() the options have not been restored: %+v", d)
+	}
+}
+
+func TestPool_ReleaseOversized(t *testing.T) {
+	p := &Pool{MaxCapacity: 8}
+
+	d := p.Acquire()
+
+	for i := 0; i <= p.MaxCapacity; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+
+	p.Release(d)
+
+	if d.D != nil {
+		t.Errorf("Pool.Release() retained cap %d over the maximum %d", cap(d.D), p.MaxCapacity)
+	}
+
+	p = &Pool{MaxCapacity: -1}
+
+	d = p.Acquire()
+
+	for i := 0; i <= MaxRetainedCapacity; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+
+	p.Release(d)
+
+	if d.D == nil {
+		t.Error("Pool.Release() dropped a backing array without limit")
+	}
+}
+
+func TestPool_ReleaseDeep(t *testing.T) {
+	p := &Pool{}
+
+	d := p.Acquire()
+	d.Parse(DictMap{"sub": map[string]interface{}{"key": "value"}})
+
+	sub, _ := d.GetDict("sub")
+
+	p.ReleaseDeep(d)
+
+	if sub.Len() != 0 {
+		t.Errorf("Pool.ReleaseDeep() owned nested dict not released: %v", sub.D)
+	}
+}
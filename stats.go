@@ -0,0 +1,101 @@
+package dictpool
+
+import (
+	"expvar"
+	"math/bits"
+	"sync/atomic"
+)
+
+// SizeHistogramBuckets is the number of buckets of a SizeHistogram.
+const SizeHistogramBuckets = 32
+
+// SizeHistogram counts sizes in power of two buckets.
+//
+// The bucket 0 counts the zero sizes and the bucket i counts the sizes
+// in [2^(i-1), 2^i), except the last one which counts all the bigger sizes.
+type SizeHistogram [SizeHistogramBuckets]uint64
+
+// PoolStats is a snapshot of the statistics of a pool.
+type PoolStats struct {
+	// Acquires is the number of acquired dicts.
+	Acquires uint64
+
+	// News is the number of acquired dicts allocated since the pool was empty.
+	News uint64
+
+	// Releases is the number of released dicts.
+	Releases uint64
+
+	// Dropped is the number of released dicts whose D was dropped for exceeding
+	// the maximum capacity.
+	Dropped uint64
+
+	// Discarded is the number of released dicts discarded by a calibrated pool.
+	Discarded uint64
+
+	// Len is the histogram of len(D) at release time, before resetting it.
+	Len SizeHistogram
+
+	// Cap is the histogram of cap(D) at release time, before dropping it.
+	Cap SizeHistogram
+}
+
+type sizeCounters [SizeHistogramBuckets]atomic.Uint64
+
+type poolCounters struct {
+	acquires  atomic.Uint64
+	news      atomic.Uint64
+	releases  atomic.Uint64
+	dropped   atomic.Uint64
+	discarded atomic.Uint64
+	lens      sizeCounters
+	caps      sizeCounters
+}
+
+// sizeBucket returns the bucket of the size in a SizeHistogram.
+func sizeBucket(n int) int {
+	return min(bits.Len(uint(n)), SizeHistogramBuckets-1)
+}
+
+func (c *sizeCounters) add(n int) {
+	c[sizeBucket(n)].Add(1)
+}
+
+func (c *sizeCounters) load() (h SizeHistogram) {
+	for i := range c {
+		h[i] = c[i].Load()
+	}
+
+	return h
+}
+
+// DefaultPool returns the pool used by AcquireDict and ReleaseDict,
+// e.g. to get its statistics. Its options must not be changed.
+func DefaultPool() *Pool {
+	return &defaultPool
+}
+
+// Stats returns a snapshot of the statistics of the pool.
+//
+// The counters are updated independently, so the snapshot could be slightly
+// inconsistent while the pool is in use.
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Acquires:  p.counters.acquires.Load(),
+		News:      p.counters.news.Load(),
+		Releases:  p.counters.releases.Load(),
+		Dropped:   p.counters.dropped.Load(),
+		Discarded: p.counters.discarded.Load(),
+		Len:       p.counters.lens.load(),
+		Cap:       p.counters.caps.load(),
+	}
+}
+
+// Publish publishes the statistics of the pool as an expvar variable with the given name.
+//
+// Like expvar.Publish, it panics if the name is already registered.
+func (p *Pool) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return p.Stats()
+	}))
+}
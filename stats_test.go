@@ -0,0 +1,114 @@
+package dictpool
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSizeBucket(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 0, want: 0},
+		{n: 1, want: 1},
+		{n: 2, want: 2},
+		{n: 3, want: 2},
+		{n: 4, want: 3},
+		{n: 1023, want: 10},
+		{n: 1024, want: 11},
+		{n: 1 << 40, want: SizeHistogramBuckets - 1},
+	}
+
+	for _, test := range tests {
+		if got := sizeBucket(test.n); got != test.want {
+			t.Errorf("sizeBucket(%d) = %d, want %d", test.n, got, test.want)
+		}
+	}
+}
+
+func TestPool_Stats(t *testing.T) {
+	p := &Pool{MaxCapacity: 4}
+
+	d1 := p.Acquire()
+	d2 := p.Acquire()
+
+	for i := 0; i < 5; i++ {
+		d1.Set(strconv.Itoa(i), i)
+	}
+
+	d2.Set("key", "value")
+
+	p.Release(d1)
+	p.Release(d2)
+
+	stats := p.Stats()
+
+	if stats.Acquires != 2 {
+		t.Errorf("Pool.Stats() Acquires = %d, want %d", stats.Acquires, 2)
+	}
+
+	if stats.News != 2 {
+		t.Errorf("Pool.Stats() News = %d, want %d", stats.News, 2)
+	}
+
+	if stats.Releases != 2 {
+		t.Errorf("Pool.Stats() Releases = %d, want %d", stats.Releases, 2)
+	}
+
+	if stats.Dropped != 1 {
+		t.Errorf("Pool.Stats() Dropped = %d, want %d", stats.Dropped, 1)
+	}
+
+	// The lengths 5 and 1 are in the buckets [4, 8) and [1, 2).
+	if stats.Len[3] != 1 || stats.Len[1] != 1 {
+		t.Errorf("Pool.Stats() Len = %v", stats.Len)
+	}
+
+	var caps uint64
+
+	for _, n := range stats.Cap {
+		caps += n
+	}
+
+	if caps != 2 {
+		t.Errorf("Pool.Stats() Cap total = %d, want %d", caps, 2)
+	}
+}
+
+// publishRuns makes the published names unique, since expvar panics on reuse
+// when the tests run more than once in the same process.
+var publishRuns atomic.Int64
+
+func TestPool_Publish(t *testing.T) {
+	p := &Pool{}
+	p.Release(p.Acquire())
+
+	name := t.Name() + "_" + strconv.FormatInt(publishRuns.Add(1), 10)
+	p.Publish(name)
+
+	var stats PoolStats
+
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats != p.Stats() {
+		t.Errorf("Pool.Publish() = %+v, want %+v", stats, p.Stats())
+	}
+}
+
+func TestDefaultPool(t *testing.T) {
+	before := DefaultPool().Stats()
+
+	ReleaseDict(AcquireDict())
+
+	after := DefaultPool().Stats()
+
+	if after.Acquires != before.Acquires+1 || after.Releases != before.Releases+1 {
+		t.Errorf("DefaultPool().Stats() = %+v, want one more acquire and release than %+v", after, before)
+	}
+}
@@ -0,0 +1,232 @@
+package dictpool
+
+import (
+	"sync"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+// DefaultSyncDictShards is the number of shards of the pooled sync dicts.
+const DefaultSyncDictShards = 16
+
+var (
+	defaultSyncPool = sync.Pool{
+		New: func() interface{} {
+			return NewSyncDict(DefaultSyncDictShards)
+		},
+	}
+
+	// rangeBufPool holds the buffers where Range copies the shards.
+	rangeBufPool = sync.Pool{
+		New: func() interface{} {
+			return new([]KV)
+		},
+	}
+)
+
+type syncShard struct {
+	mu sync.RWMutex
+	d  Dict
+}
+
+// SyncDict concurrency-safe dictionary, made of Dict shards
+// protected by their own lock and chosen by the key hash.
+//
+// The shards use the adaptive lookup strategy.
+type SyncDict struct {
+	shards  []syncShard
+	shift   uint
+	keyMode KeyMode
+}
+
+// NewSyncDict returns a new sync dict with the given number of shards,
+// rounded up to a power of two.
+func NewSyncDict(shards int) *SyncDict {
+	n, bits := 1, uint(0)
+	for n < shards {
+		n <<= 1
+		bits++
+	}
+
+	sd := &SyncDict{
+		shards: make([]syncShard, n),
+		shift:  32 - bits,
+	}
+
+	for i := range sd.shards {
+		sd.shards[i].d.SetAdaptive(true)
+	}
+
+	return sd
+}
+
+// AcquireSyncDict acquire new sync dict.
+func AcquireSyncDict() *SyncDict {
+	return defaultSyncPool.Get().(*SyncDict) // nolint:forcetypeassert
+}
+
+// ReleaseSyncDict release sync dict.
+//
+// Unlike Reset, the key arenas are reused, since a released dict has no
+// concurrent users anymore.
+func ReleaseSyncDict(sd *SyncDict) {
+	for i := range sd.shards {
+		d := &sd.shards[i].d
+
+		d.reset()
+		d.dropOversized(MaxRetainedCapacity)
+	}
+
+	sd.SetKeyMode(KeyExact)
+
+	defaultSyncPool.Put(sd)
+}
+
+// shard returns the shard of the key, chosen by the high bits of its hash
+// since the low ones are used by the hash index of the shard.
+func (sd *SyncDict) shard(key string) *syncShard {
+	return &sd.shards[sd.keyMode.hash(key)>>sd.shift]
+}
+
+// SetKeyMode sets how the keys are compared, choosing their shards consistently.
+//
+// It must be set on an empty sync dict before using it concurrently.
+func (sd *SyncDict) SetKeyMode(mode KeyMode) {
+	sd.keyMode = mode
+
+	for i := range sd.shards {
+		sd.shards[i].d.SetKeyMode(mode)
+	}
+}
+
+// Len returns the number of keys of the sync dict.
+func (sd *SyncDict) Len() int {
+	n := 0
+
+	for i := range sd.shards {
+		sh := &sd.shards[i]
+
+		sh.mu.RLock()
+		n += sh.d.len()
+		sh.mu.RUnlock()
+	}
+
+	return n
+}
+
+// Get get data from key.
+func (sd *SyncDict) Get(key string) interface{} {
+	sh := sd.shard(key)
+
+	sh.mu.RLock()
+	value := sh.d.get(key)
+	sh.mu.RUnlock()
+
+	return value
+}
+
+// GetBytes get data from key.
+func (sd *SyncDict) GetBytes(key []byte) interface{} {
+	return sd.Get(strconv.B2S(key))
+}
+
+// Set set new key.
+func (sd *SyncDict) Set(key string, value interface{}) {
+	sh := sd.shard(key)
+
+	sh.mu.Lock()
+	sh.d.set(key, value)
+	sh.mu.Unlock()
+}
+
+// SetBytes set new key.
+//
+// The key is copied if it's added, so the buffer could be reused.
+func (sd *SyncDict) SetBytes(key []byte, value interface{}) {
+	k := strconv.B2S(key)
+	sh := sd.shard(k)
+
+	sh.mu.Lock()
+	sh.d.setBytes(k, value)
+	sh.mu.Unlock()
+}
+
+// Del delete key.
+func (sd *SyncDict) Del(key string) {
+	sh := sd.shard(key)
+
+	sh.mu.Lock()
+	sh.d.del(key)
+	sh.mu.Unlock()
+}
+
+// DelBytes delete key.
+func (sd *SyncDict) DelBytes(key []byte) {
+	sd.Del(strconv.B2S(key))
+}
+
+// Has check if key exists.
+func (sd *SyncDict) Has(key string) bool {
+	sh := sd.shard(key)
+
+	sh.mu.RLock()
+	ok := sh.d.has(key)
+	sh.mu.RUnlock()
+
+	return ok
+}
+
+// HasBytes check if key exists.
+func (sd *SyncDict) HasBytes(key []byte) bool {
+	return sd.Has(strconv.B2S(key))
+}
+
+// Range calls fn sequentially for each key and value present in the sync dict.
+// If fn returns false, range stops the iteration.
+//
+// Each shard is copied before calling fn, so fn could modify the sync dict,
+// but it does not see the changes made to the shard that is being iterated.
+func (sd *SyncDict) Range(fn func(key string, value interface{}) bool) {
+	buf := rangeBufPool.Get().(*[]KV) // nolint:forcetypeassert
+
+	defer func() {
+		if !exceedsRetainedCapacity(cap(*buf)) {
+			rangeBufPool.Put(buf)
+		}
+	}()
+
+	for i := range sd.shards {
+		sh := &sd.shards[i]
+
+		sh.mu.RLock()
+		*buf = append((*buf)[:0], sh.d.D...)
+		sh.mu.RUnlock()
+
+		for j := range *buf {
+			kv := &(*buf)[j]
+
+			if !fn(kv.Key, kv.Value) {
+				clear(*buf)
+
+				return
+			}
+		}
+
+		clear(*buf)
+	}
+}
+
+// Reset reset sync dict.
+//
+// The key arenas are dropped instead of reused, since the keys could still be
+// used by a concurrent Range.
+func (sd *SyncDict) Reset() {
+	for i := range sd.shards {
+		sh := &sd.shards[i]
+
+		sh.mu.Lock()
+		sh.d.reset()
+		sh.d.keys = keyArena{}
+		sh.mu.Unlock()
+	}
+}
@@ -0,0 +1,313 @@
+package dictpool
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestNewSyncDict(t *testing.T) {
+	for shards, want := range map[int]int{0: 1, 1: 1, 3: 4, 16: 16} {
+		if got := len(NewSyncDict(shards).shards); got != want {
+			t.Errorf("NewSyncDict(%d) shards == %d, want %d", shards, got, want)
+		}
+	}
+}
+
+func TestSyncDict(t *testing.T) {
+	sd := AcquireSyncDict()
+	keys := genKeys(t, 500)
+
+	for i, k := range keys {
+		sd.Set(k, i)
+	}
+
+	sd.SetBytes([]byte("bytes"), true)
+
+	if sd.Len() != len(keys)+1 {
+		t.Errorf("SyncDict.Len() == %d, want %d", sd.Len(), len(keys)+1)
+	}
+
+	for i, k := range keys {
+		if val := sd.Get(k); val != i {
+			t.Errorf("SyncDict.Get() = '%v', want '%v'", val, i)
+		}
+	}
+
+	if sd.GetBytes([]byte("bytes")) != true || !sd.HasBytes([]byte("bytes")) {
+		t.Error("SyncDict.SetBytes() not set the new key and value")
+	}
+
+	sd.Del(keys[0])
+	sd.DelBytes([]byte("bytes"))
+
+	if sd.Has(keys[0]) || sd.HasBytes([]byte("bytes")) {
+		t.Error("SyncDict.Del() not delete the key")
+	}
+
+	for i := range sd.shards {
+		if err := sd.shards[i].d.Validate(); err != nil {
+			t.Errorf("Dict.Validate() unexpected error in shard %d: %v", i, err)
+		}
+	}
+
+	ReleaseSyncDict(sd)
+
+	if sd.Len() != 0 {
+		t.Error("the sync dict has not been reseted")
+	}
+}
+
+func TestSyncDict_Range(t *testing.T) {
+	sd := AcquireSyncDict()
+
+	for i := 0; i < 100; i++ {
+		sd.Set(strconv.Itoa(i), i)
+	}
+
+	seen := 0
+
+	sd.Range(func(key string, value interface{}) bool {
+		if key != strconv.Itoa(value.(int)) { // nolint:forcetypeassert
+			t.Errorf("SyncDict.Range() key %q with value %v", key, value)
+		}
+
+		// Modifying the sync dict from fn must not deadlock.
+		sd.Del(key)
+
+		seen++
+
+		return true
+	})
+
+	if seen != 100 || sd.Len() != 0 {
+		t.Errorf("SyncDict.Range() visited %d keys and left %d, want 100 and 0", seen, sd.Len())
+	}
+
+	sd.Set("a", 1)
+	sd.Set("b", 2)
+
+	seen = 0
+
+	sd.Range(func(key string, value interface{}) bool {
+		seen++
+
+		return false
+	})
+
+	if seen != 1 {
+		t.Errorf("SyncDict.Range() visited %d keys after stopping, want 1", seen)
+	}
+
+	ReleaseSyncDict(sd)
+}
+
+func TestSyncDict_Concurrency(t *testing.T) {
+	const goroutines, ops = 8, 2000
+
+	sd := AcquireSyncDict()
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < ops; i++ {
+				key := strconv.Itoa(i % 300)
+
+				switch i % 5 {
+				case 0, 1:
+					sd.Set(key, g)
+				case 2:
+					sd.Get(key)
+				case 3:
+					sd.Del(key)
+				default:
+					sd.Range(func(key string, value interface{}) bool {
+						return sd.Has(key)
+					})
+				}
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	for i := range sd.shards {
+		if err := sd.shards[i].d.Validate(); err != nil {
+			t.Errorf("Dict.Validate() unexpected error in shard %d: %v", i, err)
+		}
+	}
+
+	ReleaseSyncDict(sd)
+}
+
+func TestReleaseSyncDict_KeepsArena(t *testing.T) {
+	sd := AcquireSyncDict()
+
+	for i := 0; i < 100; i++ {
+		sd.SetBytes([]byte("key"+strconv.Itoa(i)), i)
+	}
+
+	ReleaseSyncDict(sd)
+
+	for i := range sd.shards {
+		d := &sd.shards[i].d
+
+		if d.Len() != 0 {
+			t.Errorf("ReleaseSyncDict() shard %d len = %d, want 0", i, d.Len())
+		}
+
+		if cap(d.keys.buf) == 0 {
+			t.Errorf("ReleaseSyncDict() shard %d key arena dropped, want reused", i)
+		}
+	}
+
+	sd.Reset()
+
+	for i := range sd.shards {
+		if cap(sd.shards[i].d.keys.buf) != 0 {
+			t.Errorf("SyncDict.Reset() shard %d key arena reused, want dropped", i)
+		}
+	}
+}
+
+func BenchmarkSyncDict(b *testing.B) {
+	keys := genKeys(b, 100)
+	sd := AcquireSyncDict()
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+
+		for pb.Next() {
+			key := keys[i%len(keys)]
+
+			if i%10 == 0 {
+				sd.Set(key, i)
+			} else {
+				sd.Get(key)
+			}
+
+			i++
+		}
+	})
+}
+
+func TestSyncDict_SetBytesOwnedKey(t *testing.T) {
+	sd := NewSyncDict(4)
+
+	buf := []byte("foo")
+	sd.SetBytes(buf, 1)
+
+	copy(buf, "bar")
+
+	if v := sd.Get("foo"); v != 1 {
+		t.Errorf("SyncDict.Get() = '%v', want '%v'", v, 1)
+	}
+}
+
+func TestSyncDict_SetBytesDelBytes(t *testing.T) {
+	sd := NewSyncDict(1)
+
+	key := []byte("0123456789abcdef")
+
+	// The shards are never reset, the bytes of the deleted keys must not be kept.
+	for i := 0; i < 100000; i++ {
+		sd.SetBytes(key, i)
+		sd.DelBytes(key)
+	}
+
+	if n := cap(sd.shards[0].d.keys.buf); n > minKeyArenaSize {
+		t.Errorf("SyncDict key arena capacity == %d, want <= %d", n, minKeyArenaSize)
+	}
+}
+
+func TestSyncDict_RangeReset(t *testing.T) {
+	sd := NewSyncDict(1)
+	sd.SetBytes([]byte("foo"), 1)
+
+	// fn could modify the sync dict, the keys it has must not be overwritten.
+	sd.Range(func(key string, value interface{}) bool {
+		sd.Reset()
+		sd.SetBytes([]byte("bar"), 2)
+
+		if key != "foo" {
+			t.Errorf("SyncDict.Range() key %q, want %q", key, "foo")
+		}
+
+		return true
+	})
+}
+
+func TestSyncDict_RangeResetConcurrency(t *testing.T) {
+	const writes = 20000
+
+	sd := NewSyncDict(1)
+
+	var wg sync.WaitGroup
+
+	done := make(chan struct{})
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			// The keys are read byte by byte so the race detector sees it.
+			sd.Range(func(key string, value interface{}) bool {
+				want := "key" + strconv.Itoa(value.(int)) // nolint:forcetypeassert
+
+				for i := 0; i < len(key) && i < len(want); i++ {
+					if key[i] != want[i] {
+						t.Errorf("SyncDict.Range() key %q, want %q", key, want)
+
+						break
+					}
+				}
+
+				return true
+			})
+		}
+	}()
+
+	for i := 0; i < writes; i++ {
+		sd.Reset()
+		sd.SetBytes([]byte("key"+strconv.Itoa(i)), i)
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestSyncDict_SetKeyMode(t *testing.T) {
+	sd := NewSyncDict(16)
+	sd.SetKeyMode(KeyFoldASCII)
+
+	for i := 0; i < 100; i++ {
+		sd.Set("Key-"+strconv.Itoa(i), i)
+	}
+
+	for i := 0; i < 100; i++ {
+		if v := sd.Get("KEY-" + strconv.Itoa(i)); v != i {
+			t.Errorf("SyncDict.Get() = '%v', want '%v'", v, i)
+		}
+	}
+
+	sd.Set("key-0", -1)
+
+	if n := sd.Len(); n != 100 {
+		t.Errorf("SyncDict.Len() = %d, want %d", n, 100)
+	}
+}
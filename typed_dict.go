@@ -0,0 +1,299 @@
+package dictpool
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/savsgio/gotils/strconv"
+)
+
+// typedPools holds a *sync.Pool per TypedDict value type.
+var typedPools sync.Map
+
+// TypedKV struct so it storages key/value data of type V.
+type TypedKV[V any] struct {
+	Key   string
+	Value V
+}
+
+// TypedDict dictionary as slice with better performance,
+// whose values are stored unboxed as V.
+type TypedDict[V any] struct {
+	// D slice of TypedKV for storage the data
+	D []TypedKV[V]
+
+	// Use binary search to the get an item.
+	// It's only useful on big heaps.
+	//
+	// WARNING: Increase searching performance on big heaps,
+	// but whe set new items could be slowier due to the sorted insertion.
+	//
+	// Use SetBinarySearch to change it on a non empty dict.
+	BinarySearch bool
+
+	// sorted reports whether D is known to be sorted by key.
+	sorted bool
+
+	// keys stores the keys set from bytes.
+	keys keyArena
+}
+
+func typedPool[V any]() *sync.Pool {
+	t := reflect.TypeOf((*V)(nil))
+
+	if p, ok := typedPools.Load(t); ok {
+		return p.(*sync.Pool) // nolint:forcetypeassert
+	}
+
+	p, _ := typedPools.LoadOrStore(t, &sync.Pool{
+		New: func() interface{} {
+			return NewTypedDict[V]()
+		},
+	})
+
+	return p.(*sync.Pool) // nolint:forcetypeassert
+}
+
+// AcquireTypedDict acquire new typed dict.
+func AcquireTypedDict[V any]() *TypedDict[V] {
+	return typedPool[V]().Get().(*TypedDict[V]) // nolint:forcetypeassert
+}
+
+// ReleaseTypedDict release typed dict.
+func ReleaseTypedDict[V any](d *TypedDict[V]) {
+	d.Reset()
+
+	d.keys.dropOversized()
+
+	if exceedsRetainedCapacity(cap(d.D)) {
+		d.D = nil
+		d.keys.buf = nil
+	}
+
+	typedPool[V]().Put(d)
+}
+
+// NewTypedDict returns a new empty typed dict.
+func NewTypedDict[V any]() *TypedDict[V] {
+	return new(TypedDict[V])
+}
+
+func (d *TypedDict[V]) allocKV() *TypedKV[V] {
+	n := d.len()
+
+	if cap(d.D) > n {
+		d.D = d.D[:n+1]
+	} else {
+		d.D = append(d.D, TypedKV[V]{}) // nolint:exhaustruct
+	}
+
+	return &d.D[n]
+}
+
+func (d *TypedDict[V]) append(key string, value V) {
+	kv := d.allocKV()
+	kv.Key = key
+	kv.Value = value
+
+	d.sorted = false
+}
+
+func (d *TypedDict[V]) insert(idx int, key string, value V) {
+	d.allocKV()
+	copy(d.D[idx+1:], d.D[idx:])
+
+	kv := &d.D[idx]
+	kv.Key = key
+	kv.Value = value
+}
+
+// search returns the position where the key is or should be inserted
+// to keep the slice sorted.
+func (d *TypedDict[V]) search(key string) int {
+	return sort.Search(d.len(), func(i int) bool {
+		return key <= d.D[i].Key
+	})
+}
+
+// ensureSorted sorts the dict if it is not known to be sorted yet.
+func (d *TypedDict[V]) ensureSorted() {
+	if d.sorted {
+		return
+	}
+
+	sort.Stable(d)
+	d.sorted = true
+}
+
+func (d *TypedDict[V]) indexOf(key string) int {
+	n := d.len()
+
+	if d.BinarySearch {
+		d.ensureSorted()
+
+		idx := d.search(key)
+
+		if idx < n && d.D[idx].Key == key {
+			return idx
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			if d.D[i].Key == key {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (d *TypedDict[V]) len() int {
+	return len(d.D)
+}
+
+func (d *TypedDict[V]) swap(i, j int) {
+	d.D[i], d.D[j] = d.D[j], d.D[i]
+}
+
+func (d *TypedDict[V]) less(i, j int) bool {
+	return d.D[i].Key < d.D[j].Key
+}
+
+func (d *TypedDict[V]) get(key string) (value V) {
+	if idx := d.indexOf(key); idx > -1 {
+		return d.D[idx].Value
+	}
+
+	return value
+}
+
+// set sets the value of the key, copying the key to the key arena
+// if it's added and it aliases a byte slice.
+func (d *TypedDict[V]) set(key string, value V, aliased bool) {
+	if !d.BinarySearch {
+		if idx := d.indexOf(key); idx > -1 {
+			d.D[idx].Value = value
+		} else {
+			d.append(d.ownKey(key, aliased), value)
+		}
+
+		return
+	}
+
+	d.ensureSorted()
+
+	if idx := d.search(key); idx < d.len() && d.D[idx].Key == key {
+		d.D[idx].Value = value
+	} else {
+		d.insert(idx, d.ownKey(key, aliased), value)
+	}
+}
+
+func (d *TypedDict[V]) ownKey(key string, aliased bool) string {
+	if aliased {
+		return d.keys.intern(key, keyRefs(d.D, (*TypedKV[V]).keyRef))
+	}
+
+	return key
+}
+
+// keyRef returns a pointer to the key.
+func (kv *TypedKV[V]) keyRef() *string {
+	return &kv.Key
+}
+
+func (d *TypedDict[V]) del(key string) {
+	if idx := d.indexOf(key); idx > -1 {
+		n := d.len()
+
+		copy(d.D[idx:], d.D[idx+1:])
+		d.D[n-1] = TypedKV[V]{} // nolint:exhaustruct
+		d.D = d.D[:n-1]
+	}
+}
+
+func (d *TypedDict[V]) has(key string) bool {
+	return d.indexOf(key) > -1
+}
+
+func (d *TypedDict[V]) reset() {
+	clear(d.D)
+
+	d.D = d.D[:0]
+	d.sorted = true
+	d.keys.reset()
+}
+
+// Len is the number of elements in the TypedDict.
+func (d *TypedDict[V]) Len() int {
+	return d.len()
+}
+
+// Swap swaps the elements with indexes i and j.
+func (d *TypedDict[V]) Swap(i, j int) {
+	d.swap(i, j)
+	d.sorted = false
+}
+
+// Less reports whether the element with
+// index i should sort before the element with index j.
+func (d *TypedDict[V]) Less(i, j int) bool {
+	return d.less(i, j)
+}
+
+// SetBinarySearch enables or disables the binary search.
+//
+// When enabled, the dict is sorted lazily on the next operation that needs it.
+func (d *TypedDict[V]) SetBinarySearch(enabled bool) {
+	d.BinarySearch = enabled
+}
+
+// Get get data from key.
+//
+// It returns the zero value of V if the key does not exist.
+func (d *TypedDict[V]) Get(key string) V {
+	return d.get(key)
+}
+
+// GetBytes get data from key.
+func (d *TypedDict[V]) GetBytes(key []byte) V {
+	return d.Get(strconv.B2S(key))
+}
+
+// Set set new key.
+func (d *TypedDict[V]) Set(key string, value V) {
+	d.set(key, value, false)
+}
+
+// SetBytes set new key.
+//
+// The key is copied if it's added, so the buffer could be reused.
+func (d *TypedDict[V]) SetBytes(key []byte, value V) {
+	d.set(strconv.B2S(key), value, true)
+}
+
+// Del delete key.
+func (d *TypedDict[V]) Del(key string) {
+	d.del(key)
+}
+
+// DelBytes delete key.
+func (d *TypedDict[V]) DelBytes(key []byte) {
+	d.Del(strconv.B2S(key))
+}
+
+// Has check if key exists.
+func (d *TypedDict[V]) Has(key string) bool {
+	return d.has(key)
+}
+
+// HasBytes check if key exists.
+func (d *TypedDict[V]) HasBytes(key []byte) bool {
+	return d.Has(strconv.B2S(key))
+}
+
+// Reset reset typed dict, reusing the memory of its keys as Dict.Reset.
+func (d *TypedDict[V]) Reset() {
+	d.reset()
+}
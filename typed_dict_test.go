@@ -0,0 +1,234 @@
+package dictpool
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestAcquireTypedDict(t *testing.T) {
+	d := AcquireTypedDict[int]()
+
+	if d == nil {
+		t.Fatal("nil typed dict")
+	}
+
+	s := AcquireTypedDict[string]()
+	s.Set("key", "value")
+
+	ReleaseTypedDict(s)
+
+	if len(s.D) > 0 {
+		t.Error("the typed dict has not been reseted")
+	}
+}
+
+func TestTypedDict_Get(t *testing.T) {
+	const k, v = "key", 1234
+
+	for _, binary := range []bool{false, true} {
+		d := AcquireTypedDict[int]()
+		d.BinarySearch = binary
+		d.Set(k, v)
+
+		if val := d.Get(k); val != v {
+			t.Errorf("TypedDict.Get() = '%v', want '%v'", val, v)
+		}
+
+		if val := d.GetBytes([]byte(k)); val != v {
+			t.Errorf("TypedDict.GetBytes() = '%v', want '%v'", val, v)
+		}
+
+		if val := d.Get("other"); val != 0 {
+			t.Errorf("TypedDict.Get() = '%v', want '%v'", val, 0)
+		}
+
+		ReleaseTypedDict(d)
+	}
+}
+
+func TestTypedDict_Set(t *testing.T) {
+	type point struct{ X, Y int }
+
+	for _, binary := range []bool{false, true} {
+		d := AcquireTypedDict[point]()
+		d.BinarySearch = binary
+
+		keys := []string{"c", "a", "d", "b"}
+		for i, k := range keys {
+			d.Set(k, point{X: i, Y: i})
+		}
+
+		d.SetBytes([]byte("a"), point{X: 10, Y: 10})
+
+		if d.Len() != len(keys) {
+			t.Errorf("TypedDict.Len() == %d, want %d", d.Len(), len(keys))
+		}
+
+		if val := d.Get("a"); val.X != 10 {
+			t.Errorf("TypedDict.Set() has not been updated the value")
+		}
+
+		if binary && !sort.IsSorted(d) {
+			t.Errorf("TypedDict.Set() the dict is not sorted: %v", d.D)
+		}
+
+		ReleaseTypedDict(d)
+	}
+}
+
+func TestTypedDict_SetBinarySearch(t *testing.T) {
+	tests := map[string]func(d *TypedDict[int]){
+		"SetBinarySearch": func(d *TypedDict[int]) { d.SetBinarySearch(true) },
+		"BinarySearch":    func(d *TypedDict[int]) { d.BinarySearch = true },
+	}
+
+	for name, enable := range tests {
+		d := AcquireTypedDict[int]()
+
+		keys := []string{"c", "a", "d", "b"}
+		for i, k := range keys {
+			d.Set(k, i)
+		}
+
+		// The filled dict is sorted by the next lookup.
+		enable(d)
+
+		for i, k := range keys {
+			if val := d.Get(k); val != i {
+				t.Errorf("%s: TypedDict.Get(%q) = '%v', want '%v'", name, k, val, i)
+			}
+		}
+
+		d.Set("e", 4)
+		d.Swap(0, d.Len()-1)
+
+		if val := d.Get("e"); val != 4 || !d.Has("a") {
+			t.Errorf("%s: TypedDict.Get(%q) = '%v', want '%v'", name, "e", val, 4)
+		}
+
+		if !sort.IsSorted(d) {
+			t.Errorf("%s: TypedDict the dict is not sorted: %v", name, d.D)
+		}
+
+		d.BinarySearch = false
+		ReleaseTypedDict(d)
+	}
+}
+
+func TestTypedDict_Del(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireTypedDict[bool]()
+		d.BinarySearch = binary
+
+		d.Set("key1", true)
+		d.Set("key2", true)
+		d.Set("key3", true)
+
+		d.Del("key2")
+		d.DelBytes([]byte("key3"))
+
+		if d.Has("key2") || d.HasBytes([]byte("key3")) {
+			t.Error("TypedDict.Del() not delete the key")
+		}
+
+		if !d.Has("key1") {
+			t.Error("TypedDict.Del() deleted another key")
+		}
+
+		ReleaseTypedDict(d)
+	}
+}
+
+func TestTypedDict_Allocs(t *testing.T) {
+	d := AcquireTypedDict[int]()
+	keys := genKeys(t, 10)
+
+	for i, k := range keys {
+		d.Set(k, i)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		for i, k := range keys {
+			d.Set(k, i+1000)
+			_ = d.Get(k)
+		}
+	})
+
+	if allocs != 0 {
+		t.Errorf("TypedDict allocs == %v, want 0", allocs)
+	}
+
+	ReleaseTypedDict(d)
+}
+
+func Benchmark_TypedGet(b *testing.B) {
+	d := AcquireTypedDict[int]()
+	keys := genKeys(b, 10)
+
+	for i := range keys {
+		d.Set(keys[i], i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = d.Get(keys[i%len(keys)])
+	}
+}
+
+func Benchmark_TypedSet(b *testing.B) {
+	d := AcquireTypedDict[int]()
+	keys := genKeys(b, 10)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.Set(keys[i%len(keys)], i)
+	}
+}
+
+func TestTypedDict_SetBytesOwnedKey(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		d := AcquireTypedDict[int]()
+		d.BinarySearch = binary
+
+		buf := []byte("foo")
+		d.SetBytes(buf, 1)
+
+		copy(buf, "bar")
+		d.SetBytes(buf, 2)
+
+		if v := d.Get("foo"); v != 1 {
+			t.Errorf("TypedDict.Get() = '%v', want '%v'", v, 1)
+		}
+
+		if v := d.Get("bar"); v != 2 {
+			t.Errorf("TypedDict.Get() = '%v', want '%v'", v, 2)
+		}
+
+		d.BinarySearch = false
+		ReleaseTypedDict(d)
+	}
+}
+
+func TestTypedDict_SetBytesDelBytes(t *testing.T) {
+	d := AcquireTypedDict[int]()
+	key := []byte("0123456789abcdef")
+
+	for i := 0; i < 100000; i++ {
+		d.SetBytes(key, i)
+		d.DelBytes(key)
+	}
+
+	if cap(d.keys.buf) > minKeyArenaSize {
+		t.Errorf("TypedDict key arena capacity == %d, want <= %d", cap(d.keys.buf), minKeyArenaSize)
+	}
+
+	d.SetBytes([]byte(strings.Repeat("x", MaxRetainedKeysSize+1)), 1)
+	ReleaseTypedDict(d)
+
+	if d.keys.buf != nil {
+		t.Errorf("ReleaseTypedDict() retained key arena cap %d over the maximum %d", cap(d.keys.buf), MaxRetainedKeysSize)
+	}
+}
@@ -1,11 +1,17 @@
 package dictpool
 
 //go:generate msgp
+//msgp:ignore Dict
 
 // KV struct so it storages key/value data.
 type KV struct {
 	Key   string
 	Value interface{}
+
+	// owned reports whether Value holds nested dicts created by the dict,
+	// which are released by ReleaseDictDeep, or when the value is replaced,
+	// deleted or the dict is loaded again.
+	owned bool
 }
 
 // Dict dictionary as slice with better performance.
@@ -17,8 +23,30 @@ type Dict struct {
 	// It's only useful on big heaps.
 	//
 	// WARNING: Increase searching performance on big heaps,
-	// but whe set new items could be slowier due to the sorting.
+	// but whe set new items could be slowier due to the sorted insertion.
+	//
+	// Use SetBinarySearch to change it on a non empty dict.
 	BinarySearch bool
+
+	// debug is zero-sized unless built with the dictpool_debug tag.
+	debug debugState
+
+	// sorted reports whether D is known to be sorted by key.
+	sorted bool
+
+	adaptive   bool
+	thresholds AdaptiveThresholds
+
+	hashed bool
+	index  hashIndex
+
+	// keys stores the keys set from bytes.
+	keys    keyArena
+	keyMode KeyMode
+	cmp     Comparator
+
+	lenientNumbers bool
+	parseSlices    bool
 }
 
 // DictMap dictionary as map.